@@ -0,0 +1,181 @@
+package main
+
+import (
+    "bufio"
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "runtime"
+    "strconv"
+    "strings"
+    "sync"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+const (
+    csvFormat   = "csv"
+    jsonlFormat = "jsonl"
+)
+
+var batchFormats = []string{csvFormat, jsonlFormat}
+
+var batchCsvHeader = []string{"line", "puzzle", "solution", "difficulty", "rating", "clues", "solved", "error"}
+
+// batchResult is the outcome for one input line, invalid puzzles only have an error
+type batchResult struct {
+    Line       int    `json:"line"`
+    Puzzle     string `json:"puzzle"`
+    Solution   string `json:"solution,omitempty"`
+    Difficulty int    `json:"difficulty"`
+    Rating     int    `json:"rating"`
+    Clues      int    `json:"clues"`
+    Solved     bool   `json:"solved"`
+    Error      string `json:"error,omitempty"`
+}
+
+type batchJob struct {
+    line   int
+    puzzle string
+    result chan batchResult
+}
+
+// runBatch solves and rates the puzzle given by the first field of every line of input on
+// multiple cores and streams the results to output in the order of the input
+func runBatch(input io.Reader, output io.Writer, format string, cores int) error {
+    if cores == -1 {
+        cores = runtime.NumCPU()
+    } else if cores < 1 {
+        return fmt.Errorf("need at least 1 core, got %d", cores)
+    }
+    jobs := make(chan batchJob)
+    // results are written in the order of this queue, its capacity limits how far the
+    // workers can get ahead of a slow puzzle
+    queue := make(chan chan batchResult, 4*cores)
+
+    var wg sync.WaitGroup
+    for i := 0; i < cores; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for job := range jobs {
+                job.result <- rateBatchPuzzle(job.line, job.puzzle)
+            }
+        }()
+    }
+
+    var readErr error
+    go func() {
+        defer close(queue)
+        defer close(jobs)
+        scanner := bufio.NewScanner(input)
+        lineNumber := 0
+        for scanner.Scan() {
+            lineNumber++
+            fields := strings.Fields(scanner.Text())
+            if len(fields) == 0 {
+                continue
+            }
+            job := batchJob{line: lineNumber, puzzle: fields[0], result: make(chan batchResult, 1)}
+            queue <- job.result
+            jobs <- job
+        }
+        readErr = scanner.Err()
+    }()
+
+    writer := newBatchWriter(output, format)
+    writeErr := writer.writeHeader()
+    for result := range queue {
+        // keep draining the queue after an error so that the workers can finish
+        if writeErr == nil {
+            writeErr = writer.write(<-result)
+        } else {
+            <-result
+        }
+    }
+    wg.Wait()
+    if writeErr != nil {
+        return writeErr
+    } else if readErr != nil {
+        return readErr
+    }
+    return writer.flush()
+}
+
+func rateBatchPuzzle(line int, puzzle string) batchResult {
+    result := batchResult{Line: line, Puzzle: puzzle}
+    board, err := sudoku.ParseBoard(puzzle)
+    if err != nil {
+        result.Error = err.Error()
+        return result
+    }
+    game, err := sudoku.New(board)
+    if err != nil {
+        result.Error = err.Error()
+        return result
+    }
+    path, solved := sudoku.SolvePath(&game)
+    result.Difficulty = sudoku.MaxDifficulty
+    if solved {
+        result.Difficulty = sudoku.PathDifficulty(path)
+    }
+    result.Puzzle = game.Board.String()
+    result.Solution = game.Solution.String()
+    result.Rating = sudoku.NumericRating(&game, path, solved)
+    result.Clues = sudoku.CountClues(game.Board)
+    result.Solved = solved
+    return result
+}
+
+type batchWriter struct {
+    format string
+    csv    *csv.Writer
+    json   *json.Encoder
+}
+
+func newBatchWriter(output io.Writer, format string) *batchWriter {
+    writer := &batchWriter{format: format}
+    if format == csvFormat {
+        writer.csv = csv.NewWriter(output)
+    } else {
+        writer.json = json.NewEncoder(output)
+    }
+    return writer
+}
+
+func (writer *batchWriter) writeHeader() error {
+    if writer.format != csvFormat {
+        return nil
+    }
+    return writer.csv.Write(batchCsvHeader)
+}
+
+// write outputs a single result, CSV rows are flushed right away so that results stream
+func (writer *batchWriter) write(result batchResult) error {
+    if writer.format == jsonlFormat {
+        return writer.json.Encode(result)
+    }
+    row := []string{strconv.Itoa(result.Line), result.Puzzle, result.Solution, "", "", "", "", result.Error}
+    if result.Error == "" {
+        row[3] = strconv.Itoa(result.Difficulty)
+        row[4] = strconv.Itoa(result.Rating)
+        row[5] = strconv.Itoa(result.Clues)
+        row[6] = strconv.FormatBool(result.Solved)
+    }
+    if err := writer.csv.Write(row); err != nil {
+        return err
+    }
+    return writer.flush()
+}
+
+func (writer *batchWriter) flush() error {
+    if writer.csv == nil {
+        return nil
+    }
+    writer.csv.Flush()
+    if err := writer.csv.Error(); err != nil {
+        return fmt.Errorf("could not write CSV: %w", err)
+    }
+    return nil
+}
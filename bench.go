@@ -0,0 +1,124 @@
+package main
+
+import (
+    "context"
+    _ "embed"
+    "fmt"
+    "io"
+    "runtime"
+    "slices"
+    "strings"
+    "text/tabwriter"
+    "time"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+//go:embed corpus/hardest.txt
+var hardestCorpus string
+
+// loadBenchCorpus parses the embedded corpus, where the puzzle is the first field of each line
+// and lines starting with # are comments
+func loadBenchCorpus() ([]sudoku.Sudoku, error) {
+    var games []sudoku.Sudoku
+    for i, line := range strings.Split(hardestCorpus, "\n") {
+        fields := strings.Fields(line)
+        if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+            continue
+        }
+        board, err := sudoku.ParseBoard(fields[0])
+        if err != nil {
+            return nil, fmt.Errorf("corpus line %d: %w", i+1, err)
+        }
+        game, err := sudoku.New(board)
+        if err != nil {
+            return nil, fmt.Errorf("corpus line %d: %w", i+1, err)
+        }
+        games = append(games, game)
+    }
+    return games, nil
+}
+
+// benchResult holds the duration and allocations of every run of a benchmark
+type benchResult struct {
+    name      string
+    durations []time.Duration
+    allocs    uint64
+    bytes     uint64
+}
+
+// measure runs f and records its duration and the allocations of the whole program while it ran,
+// a run that fails is not recorded and its error is returned
+func (result *benchResult) measure(f func() error) error {
+    var before, after runtime.MemStats
+    runtime.ReadMemStats(&before)
+    start := time.Now()
+    if err := f(); err != nil {
+        return err
+    }
+    duration := time.Since(start)
+    runtime.ReadMemStats(&after)
+    result.durations = append(result.durations, duration)
+    result.allocs += after.Mallocs - before.Mallocs
+    result.bytes += after.TotalAlloc - before.TotalAlloc
+    return nil
+}
+
+// percentile returns the duration below which p percent of the runs finished, using the nearest rank
+func (result *benchResult) percentile(p int) time.Duration {
+    sorted := slices.Clone(result.durations)
+    slices.Sort(sorted)
+    rank := (p*len(sorted) + 99) / 100
+    return sorted[max(rank, 1)-1]
+}
+
+// runBench times the generation of count puzzles with method for each difficulty up to maxLevel, and brute force
+// solving and rating of every puzzle of the embedded corpus runs times, with fixed seeds for reproducibility
+func runBench(output io.Writer, method string, count int, maxLevel int, runs int, cores int) error {
+    corpus, err := loadBenchCorpus()
+    if err != nil {
+        return err
+    }
+    var results []*benchResult
+    for difficulty := 1; difficulty <= maxLevel; difficulty++ {
+        result := &benchResult{name: fmt.Sprintf("generate/%s/difficulty-%d", method, difficulty)}
+        options := sudoku.GeneratorOptions{Method: method, Difficulty: difficulty}
+        for seed := 0; seed < count; seed++ {
+            // Generate only returns after its workers stopped, so none of them runs into the next measurement
+            err := result.measure(func() error {
+                _, err := sudoku.Generate(context.Background(), options, seed, cores)
+                return err
+            })
+            if err != nil {
+                return fmt.Errorf("%s with seed %d: %w", result.name, seed, err)
+            }
+        }
+        results = append(results, result)
+    }
+    solve := &benchResult{name: "solve/brute-force"}
+    rate := &benchResult{name: "rate/strategies"}
+    for i := 0; i < runs; i++ {
+        for _, game := range corpus {
+            solve.measure(func() error {
+                sudoku.CountSolutions(game, 2)
+                return nil
+            })
+            rate.measure(func() error {
+                sudoku.SolvePath(&game)
+                return nil
+            })
+        }
+    }
+    results = append(results, solve, rate)
+
+    writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "benchmark\truns\tp50\tp90\tp99\tmax\tallocs/op\tbytes/op")
+    for _, result := range results {
+        numRuns := uint64(len(result.durations))
+        fmt.Fprintf(writer, "%s\t%d\t%v\t%v\t%v\t%v\t%d\t%d\n", result.name, numRuns,
+            result.percentile(50).Round(time.Microsecond), result.percentile(90).Round(time.Microsecond),
+            result.percentile(99).Round(time.Microsecond), result.percentile(100).Round(time.Microsecond),
+            result.allocs/numRuns, result.bytes/numRuns)
+    }
+    return writer.Flush()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// grid sizes in cm of puzzles, two per row, and of solutions, three per row
+const (
+    bookletPuzzleSize   = 7.2
+    bookletSolutionSize = 4.5
+)
+
+type bookletPuzzle struct {
+    game       sudoku.Sudoku
+    number     int
+    difficulty int
+}
+
+// writeBooklet writes a LaTeX document with a title page, the puzzles grouped by difficulty
+// and an appendix with their solutions, to be compiled with pdflatex and TikZ
+func writeBooklet(path string, games []sudoku.Sudoku, title string) error {
+    puzzles := make([]bookletPuzzle, len(games))
+    for i, game := range games {
+        puzzles[i] = bookletPuzzle{game: game, difficulty: sudoku.Rate(&game)}
+    }
+    slices.SortStableFunc(puzzles, func(a bookletPuzzle, b bookletPuzzle) int {
+        return a.difficulty - b.difficulty
+    })
+    for i := range puzzles {
+        puzzles[i].number = i + 1
+    }
+
+    var builder strings.Builder
+    builder.WriteString("\\documentclass[a4paper,11pt]{article}\n")
+    builder.WriteString("\\usepackage[margin=2cm]{geometry}\n")
+    builder.WriteString("\\usepackage{tikz}\n")
+    builder.WriteString("\\pagestyle{plain}\n")
+    builder.WriteString("\\setlength{\\parindent}{0pt}\n\n")
+    builder.WriteString("\\begin{document}\n\n")
+
+    builder.WriteString("\\begin{titlepage}\n\\centering\n\\vspace*{6cm}\n")
+    fmt.Fprintf(&builder, "{\\Huge\\bfseries %s\\par}\n\\vspace{1.5cm}\n", escapeLatex(title))
+    fmt.Fprintf(&builder, "{\\Large %d puzzles\\par}\n\\vfill\n", len(puzzles))
+    fmt.Fprintf(&builder, "{\\large %s\\par}\n\\end{titlepage}\n", time.Now().Format("January 2006"))
+
+    for start := 0; start < len(puzzles); {
+        difficulty := puzzles[start].difficulty
+        end := start
+        for end < len(puzzles) && puzzles[end].difficulty == difficulty {
+            end++
+        }
+        fmt.Fprintf(&builder, "\n\\clearpage\n\\section*{Difficulty %d}\n\n", difficulty)
+        for i, puzzle := range puzzles[start:end] {
+            writeBookletGrid(&builder, puzzle, fmt.Sprintf("Puzzle %d", puzzle.number),
+                bookletPuzzleSize, false, "0.48\\textwidth")
+            if i%2 == 0 {
+                builder.WriteString("\\hfill\n")
+            } else {
+                builder.WriteString("\n\\vspace{1cm}\n\n")
+            }
+        }
+        start = end
+    }
+
+    builder.WriteString("\n\\clearpage\n\\appendix\n\\section*{Solutions}\n\n")
+    for i, puzzle := range puzzles {
+        writeBookletGrid(&builder, puzzle, fmt.Sprintf("Solution %d", puzzle.number),
+            bookletSolutionSize, true, "0.32\\textwidth")
+        if i%3 != 2 {
+            builder.WriteString("\\hfill\n")
+        } else {
+            builder.WriteString("\n\\vspace{0.6cm}\n\n")
+        }
+    }
+    builder.WriteString("\n\\end{document}\n")
+    return os.WriteFile(path, []byte(builder.String()), 0o644)
+}
+
+// writeBookletGrid draws a labeled board in a TikZ picture inside a minipage, for solutions
+// with the clues in black and the other digits in gray
+func writeBookletGrid(builder *strings.Builder, puzzle bookletPuzzle, label string, size float64, solution bool, width string) {
+    scale := size / 9
+    fontSize := "\\Large"
+    if solution {
+        fontSize = "\\small"
+    }
+    fmt.Fprintf(builder, "\\begin{minipage}{%s}\n\\centering\n", width)
+    fmt.Fprintf(builder, "\\textbf{%s}\\\\[0.2cm]\n", label)
+    fmt.Fprintf(builder, "\\begin{tikzpicture}[scale=%.2f]\n", scale)
+    builder.WriteString("\\draw[step=1,thin] (0,0) grid (9,9);\n")
+    builder.WriteString("\\draw[step=3,very thick] (0,0) grid (9,9);\n")
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            digit := puzzle.game.Board[i][j]
+            color := ""
+            if solution && digit == 0 {
+                digit = puzzle.game.Solution[i][j]
+                color = "[gray]"
+            }
+            if digit == 0 {
+                continue
+            }
+            fmt.Fprintf(builder, "\\node%s at (%.1f,%.1f) {%s %d};\n", color, float64(j)+0.5, 8.5-float64(i), fontSize, digit)
+        }
+    }
+    builder.WriteString("\\end{tikzpicture}\n\\end{minipage}\n")
+}
+
+func escapeLatex(text string) string {
+    replacer := strings.NewReplacer(
+        "\\", "\\textbackslash{}",
+        "{", "\\{",
+        "}", "\\}",
+        "$", "\\$",
+        "&", "\\&",
+        "#", "\\#",
+        "%", "\\%",
+        "_", "\\_",
+        "^", "\\textasciicircum{}",
+        "~", "\\textasciitilde{}",
+    )
+    return replacer.Replace(text)
+}
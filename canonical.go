@@ -0,0 +1,134 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// all permutations of three elements, used for bands, stacks and the lines within them
+var permutations3 = [6][3]int{
+    {0, 1, 2},
+    {0, 2, 1},
+    {1, 0, 2},
+    {1, 2, 0},
+    {2, 0, 1},
+    {2, 1, 0},
+}
+
+// getLineOrders returns all 1296 orders of the rows (or columns) of a board that keep rows within
+// their band, i.e. all combinations of permuting the bands and permuting the rows inside each band
+func getLineOrders() [][9]int {
+    var orders [][9]int
+    for _, bandOrder := range permutations3 {
+        for _, lineOrder0 := range permutations3 {
+            for _, lineOrder1 := range permutations3 {
+                for _, lineOrder2 := range permutations3 {
+                    var order [9]int
+                    for i, lineOrder := range [3][3]int{lineOrder0, lineOrder1, lineOrder2} {
+                        for j := 0; j < 3; j++ {
+                            order[3*i+j] = 3*bandOrder[i] + lineOrder[j]
+                        }
+                    }
+                    orders = append(orders, order)
+                }
+            }
+        }
+    }
+    return orders
+}
+
+func transposeBoard(board [9][9]uint8) [9][9]uint8 {
+    var transposed [9][9]uint8
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            transposed[j][i] = board[i][j]
+        }
+    }
+    return transposed
+}
+
+// canonicalForm returns a representative of all boards that are equivalent to board under the
+// sudoku symmetry group, i.e. relabeling of digits, permutations of rows within bands, columns
+// within stacks, bands and stacks, and transposition. Two boards are equivalent if and only if
+// their canonical forms are equal. The representative is the lexicographically smallest 81
+// character line of all equivalent boards, where digits are relabeled in order of appearance.
+func canonicalForm(board [9][9]uint8) string {
+    lineOrders := getLineOrders()
+    var best, current [81]uint8
+    for i := range best {
+        best[i] = 10
+    }
+    var labels [10]uint8
+    var nextLabel uint8
+    var isBetter bool
+    for _, orientation := range [2][9][9]uint8{board, transposeBoard(board)} {
+        for _, rowOrder := range lineOrders {
+        columnLoop:
+            for _, colOrder := range lineOrders {
+                labels = [10]uint8{}
+                nextLabel = 1
+                isBetter = false
+                for i := 0; i < 9; i++ {
+                    for j := 0; j < 9; j++ {
+                        value := orientation[rowOrder[i]][colOrder[j]]
+                        if value != 0 {
+                            if labels[value] == 0 {
+                                labels[value] = nextLabel
+                                nextLabel++
+                            }
+                            value = labels[value]
+                        }
+                        cellIdx := 9*i + j
+                        if !isBetter {
+                            if value > best[cellIdx] {
+                                continue columnLoop
+                            } else if value < best[cellIdx] {
+                                isBetter = true
+                            }
+                        }
+                        current[cellIdx] = value
+                    }
+                }
+                if isBetter {
+                    best = current
+                }
+            }
+        }
+    }
+    var canonicalBoard [9][9]uint8
+    for cellIdx, value := range best {
+        canonicalBoard[cellIdx/9][cellIdx%9] = value
+    }
+    return sudoku.Board(canonicalBoard).String()
+}
+
+// runCanon prints the canonical form of every puzzle read from input, given by the first 81 character
+// field of each line, and marks puzzles that are equivalent to an earlier one
+func runCanon(input io.Reader) error {
+    scanner := bufio.NewScanner(input)
+    firstLines := make(map[string]int)
+    lineNumber := 0
+    for scanner.Scan() {
+        lineNumber++
+        fields := strings.Fields(scanner.Text())
+        if len(fields) == 0 {
+            continue
+        }
+        board, err := sudoku.ParseBoard(fields[0])
+        if err != nil {
+            return fmt.Errorf("line %d: %w", lineNumber, err)
+        }
+        canonical := canonicalForm(board)
+        if firstLine, ok := firstLines[canonical]; ok {
+            fmt.Printf("%s duplicate of line %d\n", canonical, firstLine)
+        } else {
+            firstLines[canonical] = lineNumber
+            fmt.Println(canonical)
+        }
+    }
+    return scanner.Err()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+    "math/rand/v2"
+    "testing"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+var canonicalTestPuzzles = []string{
+    "050008000002050006308072094980040010500006000103020800209030058000100602070260409",
+    "030900610670500040002000003000000400380407000700300000400803900018005360593206008",
+    "020800907010000004000700080146503700000017006807042315075000008900000070381000069",
+    "000600324000030085000040007073100000006000000000057000095004001008000070102095803",
+}
+
+func TestCanonicalFormIsInvariantUnderTransformations(t *testing.T) {
+    lists := []string{"random", "relabel", "rotate", "mirror", "flip", "transpose", "bands", "rows", "stacks", "columns",
+        "rotate,rotate", "bands,stacks,relabel,transpose"}
+    for _, line := range canonicalTestPuzzles {
+        board, err := sudoku.ParseBoard(line)
+        if err != nil {
+            t.Fatalf("could not parse %s: %v", line, err)
+        }
+        canonical := canonicalForm(board)
+        for _, list := range lists {
+            for seed := range 3 {
+                rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+                transformations, err := parseTransformations(list, rng)
+                if err != nil {
+                    t.Fatalf("could not parse transformations %q: %v", list, err)
+                }
+                transformed := board
+                for _, transformation := range transformations {
+                    transformed = transformation(transformed)
+                }
+                if got := canonicalForm(transformed); got != canonical {
+                    t.Errorf("canonical form of %s after %q with seed %d is %s, want %s", line, list, seed, got, canonical)
+                }
+            }
+        }
+    }
+}
+
+func TestCanonicalFormDistinguishesPuzzles(t *testing.T) {
+    seen := map[string]string{}
+    for _, line := range canonicalTestPuzzles {
+        board, err := sudoku.ParseBoard(line)
+        if err != nil {
+            t.Fatalf("could not parse %s: %v", line, err)
+        }
+        canonical := canonicalForm(board)
+        if other, ok := seen[canonical]; ok {
+            t.Errorf("%s and %s have the same canonical form %s", line, other, canonical)
+        }
+        seen[canonical] = line
+    }
+}
@@ -0,0 +1,746 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "os"
+    "runtime/pprof"
+    "slices"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// command is a subcommand of the CLI, setup defines its flags on flags and returns the
+// function that runs it with the remaining arguments, errors are reported by runCommand
+type command struct {
+    name        string
+    args        string
+    description string
+    setup       func(flags *flag.FlagSet) commandRunner
+}
+
+// commandRunner runs a command with the arguments left after parsing its flags
+type commandRunner func(args []string) error
+
+// defaultCommand is run if no command is given, e.g. for "sugoku -difficulty 3"
+const defaultCommand = "play"
+
+var commands = []command{
+    {"play", "", "play a generated or given sudoku in the terminal (default)", playCommand},
+    {"generate", "", "generate sudokus and print them as \"puzzle solution\" lines", generateCommand},
+    {"print", "", "print a generated or given sudoku and its solution", printCommand},
+    {"serve", "", "play generated sudokus in the browser, served by a local web server", serveCommand},
+    {"solve", "<puzzle|-|file>", "print the solution of puzzles as \"puzzle solution\" lines", solveCommand},
+    {"rate", "<puzzle|-|file>", "print the difficulty and numeric rating of puzzles as \"puzzle difficulty rating\" lines", rateCommand},
+    {"validate", "<puzzle|-|file>", "report duplicate digits, cells without candidates and missing or multiple solutions of puzzles", validateCommand},
+    {"explain", "<puzzle|-|file>", "print every step the strategies take to solve puzzles", explainCommand},
+    {"batch", "[file|-]", "solve and rate the puzzles of a file or stdin, one per line, on multiple cores and stream the results", batchCommand},
+    {"bench", "", "time generation, brute force solving and rating on an embedded corpus of hard puzzles", benchCommand},
+    {"export", "<file>", "write generated or given sudokus to a .sdk, .sdm or .ss file", exportCommand},
+    {"sheet", "<file>", "render generated or given sudokus to a printable .svg or .pdf file", sheetCommand},
+    {"booklet", "<file>", "write generated or given sudokus to a LaTeX booklet grouped by difficulty", bookletCommand},
+    {"render", "<file>", "render a generated or given sudoku to a .png file", renderCommand},
+    {"canon", "", "read puzzles from stdin, one 81 character line each, and print their canonical forms", canonCommand},
+    {"transform", "<list>", "read puzzles from stdin and print variants created by a comma separated list of transformations", transformCommand},
+}
+
+func findCommand(name string) (command, bool) {
+    for _, cmd := range commands {
+        if cmd.name == name {
+            return cmd, true
+        }
+    }
+    return command{}, false
+}
+
+func printUsage() {
+    output := flag.CommandLine.Output()
+    fmt.Fprintf(output, "Usage: sugoku [command] [flags] [arguments]\n\nCommands:\n")
+    for _, cmd := range commands {
+        fmt.Fprintf(output, "  %-10s %s\n", cmd.name, cmd.description)
+    }
+    fmt.Fprintf(output, "\nRun \"sugoku help <command>\" for the flags of a command.\n")
+}
+
+// runCommand picks the command from the first argument, falling back to the default command
+// if there is none or it is a flag, and exits with the exit code of the class of its error
+func runCommand(args []string) {
+    name := defaultCommand
+    if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+        name, args = args[0], args[1:]
+    } else {
+        name, args = mapPrintFlag(args)
+    }
+    if name == "help" {
+        if len(args) == 0 {
+            printUsage()
+            return
+        }
+        name, args = args[0], []string{"-h"}
+    }
+    cmd, ok := findCommand(name)
+    if !ok {
+        printUsage()
+        exitWithError(newUsageError("unknown command %q", name))
+    }
+    flags, run := newCommandFlags(cmd)
+    if err := applyConfig(flags, cmd); err != nil {
+        exitWithError(err)
+    }
+    flags.Parse(args)
+    if err := run(flags.Args()); err != nil {
+        exitWithError(err)
+    }
+}
+
+// mapPrintFlag keeps "sugoku -print [flags]" of the CLI without commands working by
+// running the print command for it, -print=false runs the default command
+func mapPrintFlag(args []string) (string, []string) {
+    name := defaultCommand
+    var rest []string
+    for _, arg := range args {
+        switch arg {
+        case "-print", "--print", "-print=true", "--print=true":
+            name = "print"
+        case "-print=false", "--print=false":
+            name = defaultCommand
+        default:
+            rest = append(rest, arg)
+        }
+    }
+    return name, rest
+}
+
+// newCommandFlags defines the flags of cmd together with the flags all commands share and
+// returns a runner that profiles cmd if requested, writing the profile when cmd returns
+func newCommandFlags(cmd command) (*flag.FlagSet, commandRunner) {
+    flags := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+    flags.Usage = func() {
+        output := flags.Output()
+        fmt.Fprintf(output, "Usage: sugoku %s [flags] %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.description)
+        flags.PrintDefaults()
+    }
+    run := cmd.setup(flags)
+    cpuprofile := flags.String("cpuprofile", "", "write cpu profile to `file`")
+    return flags, func(args []string) (err error) {
+        if *cpuprofile != "" {
+            f, err := os.Create(*cpuprofile)
+            if err != nil {
+                return fmt.Errorf("could not create CPU profile: %w", err)
+            }
+            if err := pprof.StartCPUProfile(f); err != nil {
+                f.Close()
+                return fmt.Errorf("could not start CPU profile: %w", err)
+            }
+            defer func() {
+                pprof.StopCPUProfile()
+                if closeErr := f.Close(); closeErr != nil && err == nil {
+                    err = fmt.Errorf("could not write CPU profile: %w", closeErr)
+                }
+            }()
+        }
+        return run(args)
+    }
+}
+
+// isFlagSet returns whether the flag called name was given on the command line
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+    isSet := false
+    flags.Visit(func(f *flag.Flag) {
+        if f.Name == name {
+            isSet = true
+        }
+    })
+    return isSet
+}
+
+// getCommandArg returns the argument of a command that takes exactly one
+func getCommandArg(flags *flag.FlagSet, args []string) (string, error) {
+    if len(args) != 1 {
+        flags.Usage()
+        return "", newUsageError("%s takes exactly one argument, got %d", flags.Name(), len(args))
+    }
+    return args[0], nil
+}
+
+// generatorFlags are the flags of all commands that generate sudokus
+type generatorFlags struct {
+    flags      *flag.FlagSet
+    seed       *int
+    cores      *int
+    difficulty *int
+    method     *string
+    symmetric  *bool
+    maskPath   *string
+    minimal    *bool
+    minClues   *int
+    maxClues   *int
+    strategy   *string
+    hardest    *bool
+}
+
+func addGeneratorFlags(flags *flag.FlagSet) *generatorFlags {
+    return &generatorFlags{
+        flags:      flags,
+        seed:       flags.Int("seed", -1, "seed for random number generator, -1 for random seed"),
+        cores:      flags.Int("cores", -1, "number of cores to use, -1 for all cores"),
+        difficulty: flags.Int("difficulty", 0, "difficulty of the generated sudoku, 0 for random difficulty (default 0)"),
+        method:     flags.String("method", sudoku.FillMethod, "generation `method`, \"fill\" to fill an empty grid or \"dig\" to remove clues from a solved grid"),
+        symmetric:  flags.Bool("symmetric", false, "remove clues in rotationally symmetric pairs, only used by -method dig"),
+        maskPath:   flags.String("mask", "", "`file` or string of 81 'x' (clue) and '.' (empty) characters the clues of the generated sudoku have to match"),
+        minimal:    flags.Bool("minimal", false, "remove clues from the generated sudoku until every clue is necessary, in symmetric pairs with -symmetric"),
+        minClues:   flags.Int("min-clues", 0, "minimum number of clues of the generated sudoku, 0 for no minimum"),
+        maxClues:   flags.Int("max-clues", 0, "maximum number of clues of the generated sudoku, 0 for no maximum"),
+        strategy:   flags.String("require-strategy", "", "name of a `strategy` the generated sudoku has to require, e.g. \"X-Wing\""),
+        hardest:    flags.Bool("require-hardest", false, "require the strategy given by -require-strategy to be the hardest one needed"),
+    }
+}
+
+// options returns the generator options of the flags, their combination is checked by Validate,
+// a difficulty of 0 is left to Generate, which picks a random one or that of the required strategy
+func (f *generatorFlags) options() (sudoku.GeneratorOptions, error) {
+    if err := checkCores(*f.cores); err != nil {
+        return sudoku.GeneratorOptions{}, err
+    } else if *f.strategy != "" {
+        if _, ok := sudoku.LookupStrategy(*f.strategy); !ok {
+            return sudoku.GeneratorOptions{}, newUsageError("unknown strategy %q, must be one of %q", *f.strategy, getStrategyNames())
+        }
+    }
+
+    options := sudoku.GeneratorOptions{
+        Method:           *f.method,
+        Difficulty:       *f.difficulty,
+        Symmetric:        *f.symmetric,
+        Minimal:          *f.minimal,
+        MinClues:         *f.minClues,
+        MaxClues:         *f.maxClues,
+        RequiredStrategy: *f.strategy,
+        RequireHardest:   *f.hardest,
+    }
+    if *f.maskPath != "" {
+        mask, err := loadMask(*f.maskPath)
+        if err != nil {
+            return sudoku.GeneratorOptions{}, newUsageError("could not load mask: %v", err)
+        }
+        options.Mask = &mask
+        // the mask replaces the method, so only a method given on the command line conflicts with it
+        // and not one from the config
+        if !isFlagSet(f.flags, "method") {
+            options.Method = sudoku.FillMethod
+        }
+    }
+    if err := options.Validate(); err != nil {
+        return sudoku.GeneratorOptions{}, newUsageError("%v", err)
+    }
+    return options, nil
+}
+
+// checkCores rejects core counts other than -1 for all cores, which would leave no worker to do the work
+func checkCores(cores int) error {
+    if cores != -1 && cores < 1 {
+        return newUsageError("cores must be -1 or at least 1, got %d", cores)
+    }
+    return nil
+}
+
+// generate returns games if puzzles were given and otherwise generates count new ones
+func (f *generatorFlags) generate(games []sudoku.Sudoku, count int) ([]sudoku.Sudoku, error) {
+    if len(games) > 0 {
+        return games, nil
+    }
+    options, err := f.options()
+    if err != nil {
+        return nil, err
+    }
+    return sudoku.GenerateMany(context.Background(), options, *f.seed, *f.cores, count)
+}
+
+// inputFlags are the flags of commands that take given puzzles or mid-solve states
+// instead of generating sudokus
+type inputFlags struct {
+    puzzle    *string
+    marksPath *string
+    load      *string
+}
+
+// addInputFlags adds -puzzle, and with states also -pencilmarks and -load
+func addInputFlags(flags *flag.FlagSet, states bool) *inputFlags {
+    f := &inputFlags{
+        puzzle: flags.String("puzzle", "", "use the given 81 character `puzzle` with 0 or . for empty cells, - to read it from stdin, or the puzzles of a .sdk, .sdm or .ss file"),
+    }
+    if states {
+        f.marksPath = flags.String("pencilmarks", "", "use a mid-solve state from a pencil mark grid `file`")
+        f.load = flags.String("load", "", "continue the game of a share `code`, as shown by the s key")
+    }
+    return f
+}
+
+// inputGames are the given puzzles, the mid-solve state of a pencil mark grid whose candidates
+// have to be kept, or a game shared with its entered digits and pencil marks
+type inputGames struct {
+    games       []sudoku.Sudoku
+    marksGame   *sudoku.Sudoku
+    sharedState *sudoku.Sudoku
+}
+
+func (f *inputFlags) read() (inputGames, error) {
+    var input inputGames
+    if *f.puzzle != "" {
+        games, err := loadPuzzles(*f.puzzle, os.Stdin)
+        if err != nil {
+            return input, fmt.Errorf("could not load puzzle: %w", err)
+        }
+        input.games = games
+    }
+    if f.marksPath != nil && *f.marksPath != "" {
+        game, err := readPencilMarksFile(*f.marksPath)
+        if err != nil {
+            return input, fmt.Errorf("could not load pencil marks: %w", err)
+        }
+        input.marksGame = &game
+        input.games = []sudoku.Sudoku{game}
+    }
+    if f.load != nil && *f.load != "" {
+        puzzle, state, err := decodeShareCode(*f.load)
+        if err != nil {
+            return input, fmt.Errorf("could not load share code: %w", err)
+        }
+        input.sharedState = &state
+        input.games = []sudoku.Sudoku{puzzle}
+    }
+    return input, nil
+}
+
+func playCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    inputs := addInputFlags(flags, true)
+    poolSize := flags.Int("pool", 3, "number of puzzles per difficulty to pre-generate in the cache for new games, 0 to disable")
+    return func(args []string) error {
+        options, err := generator.options()
+        if err != nil {
+            return err
+        }
+        input, err := inputs.read()
+        if err != nil {
+            return err
+        }
+        m, err := initialTuiModel(input.games, options, *generator.seed, *generator.cores, *poolSize)
+        if err != nil {
+            return err
+        }
+        if input.sharedState != nil {
+            m = m.withState(*input.sharedState)
+        } else if input.marksGame != nil {
+            m = m.withPencilMarks(*input.marksGame)
+        }
+        return runTui(m)
+    }
+}
+
+func generateCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    count := flags.Int("count", 1, "number of puzzles to generate")
+    format := addLineFormatFlag(flags)
+    return func(args []string) error {
+        if err := checkLineFormat(*format); err != nil {
+            return err
+        }
+        options, err := generator.options()
+        if err != nil {
+            return err
+        }
+        for i := 0; i < *count; i++ {
+            seed := *generator.seed
+            if seed != -1 {
+                seed += i
+            }
+            if *format == jsonFormat {
+                if err := runPrintJson(nil, options, seed, *generator.cores); err != nil {
+                    return err
+                }
+                continue
+            }
+            game, err := sudoku.Generate(context.Background(), options, seed, *generator.cores)
+            if err != nil {
+                return err
+            }
+            fmt.Printf("%s %s\n", game.Board.String(), game.Solution.String())
+        }
+        return nil
+    }
+}
+
+func printCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    inputs := addInputFlags(flags, true)
+    format := flags.String("format", textFormat, "output `format`, \"text\" or \"json\"")
+    return func(args []string) error {
+        if !slices.Contains(outputFormats, *format) {
+            return newUsageError("format must be one of %q", outputFormats)
+        }
+        input, err := inputs.read()
+        if err != nil {
+            return err
+        }
+        if input.marksGame != nil {
+            runPencilMarks(*input.marksGame)
+            return nil
+        }
+        var options sudoku.GeneratorOptions
+        if len(input.games) == 0 {
+            if options, err = generator.options(); err != nil {
+                return err
+            }
+        }
+        return runPrint(input.games, options, *generator.seed, *generator.cores, *format)
+    }
+}
+
+func serveCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    addr := flags.String("addr", "localhost:8080", "`address` to listen on")
+    return func(args []string) error {
+        options, err := generator.options()
+        if err != nil {
+            return err
+        }
+        if err := runServe(*addr, options, *generator.seed, *generator.cores); err != nil {
+            return fmt.Errorf("could not serve sudokus: %w", err)
+        }
+        return nil
+    }
+}
+
+// loadCommandPuzzles loads the puzzles given as the argument of a command
+func loadCommandPuzzles(flags *flag.FlagSet, args []string) ([]sudoku.Sudoku, error) {
+    puzzle, err := getCommandArg(flags, args)
+    if err != nil {
+        return nil, err
+    }
+    games, err := loadPuzzles(puzzle, os.Stdin)
+    if err != nil {
+        return nil, fmt.Errorf("could not load puzzle: %w", err)
+    }
+    return games, nil
+}
+
+// addLineFormatFlag adds -format for commands that print a line or a line of JSON per puzzle
+func addLineFormatFlag(flags *flag.FlagSet) *string {
+    return flags.String("format", lineFormat, "output `format`, \"line\" or \"json\"")
+}
+
+func checkLineFormat(format string) error {
+    if format != lineFormat && format != jsonFormat {
+        return newUsageError("format must be one of %q", []string{lineFormat, jsonFormat})
+    }
+    return nil
+}
+
+// writeJsonSudokus writes the puzzles in the JSON schema of print -format json, one line each
+func writeJsonSudokus(games []sudoku.Sudoku) error {
+    for _, game := range games {
+        if err := writeJsonSudoku(os.Stdout, game, nil); err != nil {
+            return fmt.Errorf("could not write JSON: %w", err)
+        }
+    }
+    return nil
+}
+
+func solveCommand(flags *flag.FlagSet) commandRunner {
+    format := addLineFormatFlag(flags)
+    return func(args []string) error {
+        if err := checkLineFormat(*format); err != nil {
+            return err
+        }
+        games, err := loadCommandPuzzles(flags, args)
+        if err != nil {
+            return err
+        }
+        if *format == jsonFormat {
+            return writeJsonSudokus(games)
+        }
+        for _, game := range games {
+            fmt.Printf("%s %s\n", game.Board.String(), game.Solution.String())
+        }
+        return nil
+    }
+}
+
+func rateCommand(flags *flag.FlagSet) commandRunner {
+    format := addLineFormatFlag(flags)
+    return func(args []string) error {
+        if err := checkLineFormat(*format); err != nil {
+            return err
+        }
+        games, err := loadCommandPuzzles(flags, args)
+        if err != nil {
+            return err
+        }
+        if *format == jsonFormat {
+            return writeJsonSudokus(games)
+        }
+        for _, game := range games {
+            path, solved := sudoku.SolvePath(&game)
+            difficulty := sudoku.MaxDifficulty
+            if solved {
+                difficulty = sudoku.PathDifficulty(path)
+            }
+            rating := sudoku.NumericRating(&game, path, solved)
+            fmt.Printf("%s %d %d\n", game.Board.String(), difficulty, rating)
+        }
+        return nil
+    }
+}
+
+func validateCommand(flags *flag.FlagSet) commandRunner {
+    return func(args []string) error {
+        puzzle, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        }
+        boards, err := loadBoards(puzzle, os.Stdin)
+        if err != nil {
+            return fmt.Errorf("could not load puzzle: %w", err)
+        }
+        numInvalid := 0
+        for i, board := range boards {
+            if i > 0 {
+                fmt.Println()
+            }
+            if !runValidate(board) {
+                numInvalid++
+            }
+        }
+        if numInvalid > 0 {
+            return fmt.Errorf("%d of %d puzzles are invalid", numInvalid, len(boards))
+        }
+        return nil
+    }
+}
+
+func explainCommand(flags *flag.FlagSet) commandRunner {
+    boards := flags.Bool("boards", false, "print the board after every placed digit")
+    return func(args []string) error {
+        games, err := loadCommandPuzzles(flags, args)
+        if err != nil {
+            return err
+        }
+        for i, game := range games {
+            if i > 0 {
+                fmt.Println()
+            }
+            runExplain(game, *boards)
+        }
+        return nil
+    }
+}
+
+func batchCommand(flags *flag.FlagSet) commandRunner {
+    cores := flags.Int("cores", -1, "number of cores to use, -1 for all cores")
+    format := flags.String("format", csvFormat, "output `format`, \"csv\" or \"jsonl\"")
+    return func(args []string) error {
+        if !slices.Contains(batchFormats, *format) {
+            return newUsageError("format must be one of %q", batchFormats)
+        } else if err := checkCores(*cores); err != nil {
+            return err
+        }
+        input := os.Stdin
+        if len(args) > 1 {
+            flags.Usage()
+            return newUsageError("batch takes at most one argument, got %d", len(args))
+        } else if len(args) == 1 && args[0] != "-" {
+            file, err := os.Open(args[0])
+            if err != nil {
+                return fmt.Errorf("could not open puzzles: %w", err)
+            }
+            defer file.Close()
+            input = file
+        }
+        if err := runBatch(input, os.Stdout, *format, *cores); err != nil {
+            return fmt.Errorf("could not rate puzzles: %w", err)
+        }
+        return nil
+    }
+}
+
+func benchCommand(flags *flag.FlagSet) commandRunner {
+    count := flags.Int("count", 3, "number of puzzles to generate per difficulty")
+    maxLevel := flags.Int("max-difficulty", 2, "highest difficulty to generate puzzles of, 0 to skip generation")
+    method := flags.String("method", sudoku.FillMethod, "generation `method`, \"fill\" or \"dig\"")
+    runs := flags.Int("runs", 10, "number of times to solve and rate each puzzle of the corpus")
+    cores := flags.Int("cores", -1, "number of cores to use for generation, -1 for all cores")
+    return func(args []string) error {
+        if *count < 1 || *runs < 1 {
+            return newUsageError("count and runs must be at least 1")
+        } else if *maxLevel < 0 || *maxLevel > sudoku.MaxDifficulty {
+            return newUsageError("max-difficulty must be between 0 and %d", sudoku.MaxDifficulty)
+        } else if !slices.Contains(sudoku.GenerationMethods, *method) {
+            return newUsageError("method must be one of %q", sudoku.GenerationMethods)
+        } else if err := checkCores(*cores); err != nil {
+            return err
+        }
+        if err := runBench(os.Stdout, *method, *count, *maxLevel, *runs, *cores); err != nil {
+            return fmt.Errorf("could not run benchmark: %w", err)
+        }
+        return nil
+    }
+}
+
+func exportCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    inputs := addInputFlags(flags, false)
+    count := flags.Int("count", 1, "number of puzzles to generate, only .sdm files can contain more than one")
+    return func(args []string) error {
+        path, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        }
+        games, err := generateCommandGames(generator, inputs, *count, func(numGames int) error {
+            return checkPuzzleFile(path, numGames)
+        })
+        if err != nil {
+            return err
+        }
+        if err := writePuzzleFile(path, games); err != nil {
+            return fmt.Errorf("could not export puzzles: %w", err)
+        }
+        return nil
+    }
+}
+
+func sheetCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    inputs := addInputFlags(flags, false)
+    count := flags.Int("count", 1, "number of puzzles to generate")
+    perPage := flags.Int("per-page", 4, "number of puzzles per page, 1, 2, 4 or 6")
+    solutions := flags.Bool("solutions", false, "add pages with the solutions")
+    labels := flags.Bool("labels", true, "label the puzzles with their number and difficulty")
+    return func(args []string) error {
+        path, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        } else if *count < 1 {
+            return newUsageError("count must be at least 1")
+        }
+        options := sheetOptions{
+            perPage:   *perPage,
+            solutions: *solutions,
+            labels:    *labels,
+        }
+        games, err := generateCommandGames(generator, inputs, *count, func(int) error {
+            _, err := newSheetCanvas(path, options)
+            return err
+        })
+        if err != nil {
+            return err
+        }
+        if err := writeSheets(path, games, options); err != nil {
+            return fmt.Errorf("could not write sheets: %w", err)
+        }
+        return nil
+    }
+}
+
+func bookletCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    inputs := addInputFlags(flags, false)
+    count := flags.Int("count", 1, "number of puzzles to generate")
+    title := flags.String("title", "Sudoku", "title of the booklet")
+    return func(args []string) error {
+        path, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        } else if *count < 1 {
+            return newUsageError("count must be at least 1")
+        }
+        games, err := generateCommandGames(generator, inputs, *count, nil)
+        if err != nil {
+            return err
+        }
+        if err := writeBooklet(path, games, *title); err != nil {
+            return fmt.Errorf("could not write booklet: %w", err)
+        }
+        return nil
+    }
+}
+
+// generateCommandGames returns the given puzzles or generates count new ones, check, if not nil, is called
+// with the number of puzzles before any are generated, so that invalid output is reported right away
+func generateCommandGames(generator *generatorFlags, inputs *inputFlags, count int, check func(numGames int) error) ([]sudoku.Sudoku, error) {
+    input, err := inputs.read()
+    if err != nil {
+        return nil, err
+    }
+    numGames := count
+    if len(input.games) > 0 {
+        numGames = len(input.games)
+    }
+    if check != nil {
+        if err := check(numGames); err != nil {
+            return nil, err
+        }
+    }
+    return generator.generate(input.games, count)
+}
+
+func renderCommand(flags *flag.FlagSet) commandRunner {
+    generator := addGeneratorFlags(flags)
+    inputs := addInputFlags(flags, true)
+    candidates := flags.Bool("candidates", false, "draw the candidates of empty cells")
+    hint := flags.Bool("hint", false, "highlight the next hint, implies -candidates")
+    cellSize := flags.Int("cell-size", 60, fmt.Sprintf("size of a cell in pixels, at least %d", minCellSize))
+    return func(args []string) error {
+        path, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        }
+        if *cellSize < minCellSize {
+            return newUsageError("cell-size must be at least %d", minCellSize)
+        }
+        input, err := inputs.read()
+        if err != nil {
+            return err
+        }
+        games, err := generator.generate(input.games, 1)
+        if err != nil {
+            return err
+        }
+        givens := getGivens(games[0].Board)
+        if input.sharedState != nil {
+            games[0] = *input.sharedState
+        }
+        states := input.marksGame != nil || input.sharedState != nil
+        if err := runRender(path, games[0], givens, *candidates || states, *hint, *cellSize); err != nil {
+            return fmt.Errorf("could not render puzzle: %w", err)
+        }
+        return nil
+    }
+}
+
+func canonCommand(flags *flag.FlagSet) commandRunner {
+    return func(args []string) error {
+        if err := runCanon(os.Stdin); err != nil {
+            return fmt.Errorf("could not canonicalize puzzles: %w", err)
+        }
+        return nil
+    }
+}
+
+func transformCommand(flags *flag.FlagSet) commandRunner {
+    variants := flags.Int("variants", 1, "number of variants to print per puzzle")
+    seed := flags.Int("seed", -1, "seed for random number generator, -1 for random seed")
+    return func(args []string) error {
+        list, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        } else if *variants < 1 {
+            return newUsageError("variants must be at least 1")
+        }
+        if err := runTransform(os.Stdin, list, *variants, *seed); err != nil {
+            return fmt.Errorf("could not transform puzzles: %w", err)
+        }
+        return nil
+    }
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "slices"
+    "strconv"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+const (
+    bashShell = "bash"
+    zshShell  = "zsh"
+    fishShell = "fish"
+)
+
+var completionShells = []string{bashShell, zshShell, fishShell}
+
+func init() {
+    // completion and man list all commands, so they are added here to avoid an initialization cycle
+    commands = append(commands,
+        command{"completion", "<bash|zsh|fish>", "print a completion script for bash, zsh or fish", completionCommand},
+        command{"man", "", "print the man page in roff format", manCommand},
+    )
+}
+
+// commandFormats are the output formats of the commands with a -format flag
+var commandFormats = map[string][]string{
+    "generate": {lineFormat, jsonFormat},
+    "solve":    {lineFormat, jsonFormat},
+    "rate":     {lineFormat, jsonFormat},
+    "print":    outputFormats,
+    "batch":    batchFormats,
+}
+
+// completionFlag is a flag of a command with everything needed to complete it
+type completionFlag struct {
+    name      string
+    valueName string
+    usage     string
+    defValue  string
+    isBool    bool
+    files     bool
+    values    []string
+}
+
+// getStrategyNames returns the names of all strategies sorted by difficulty
+func getStrategyNames() []string {
+    var names []string
+    for _, strategy := range sudoku.Strategies() {
+        names = append(names, strategy.Name())
+    }
+    return names
+}
+
+// getFlagValues returns the values flag name of command cmdName can take, or nil if they are not a fixed list
+func getFlagValues(cmdName string, name string) []string {
+    switch name {
+    case "difficulty":
+        values := make([]string, len(sudoku.ValidDifficulties))
+        for i, difficulty := range sudoku.ValidDifficulties {
+            values[i] = strconv.Itoa(difficulty)
+        }
+        return values
+    case "require-strategy":
+        return getStrategyNames()
+    case "method":
+        return sudoku.GenerationMethods
+    case "format":
+        return commandFormats[cmdName]
+    case "per-page":
+        var values []string
+        for perPage := range sheetLayouts {
+            values = append(values, strconv.Itoa(perPage))
+        }
+        slices.Sort(values)
+        return values
+    }
+    return nil
+}
+
+// getCompletionFlags returns the flags of cmd sorted by name
+func getCompletionFlags(cmd command) []completionFlag {
+    flags, _ := newCommandFlags(cmd)
+    var completionFlags []completionFlag
+    flags.VisitAll(func(f *flag.Flag) {
+        valueName, usage := flag.UnquoteUsage(f)
+        boolFlag, isBool := f.Value.(interface{ IsBoolFlag() bool })
+        completionFlags = append(completionFlags, completionFlag{
+            name:      f.Name,
+            valueName: valueName,
+            usage:     usage,
+            defValue:  f.DefValue,
+            isBool:    isBool && boolFlag.IsBoolFlag(),
+            files:     valueName == "file" || f.Name == "puzzle",
+            values:    getFlagValues(cmd.name, f.Name),
+        })
+    })
+    return completionFlags
+}
+
+// getArgValues returns the values the argument of cmd can take, or nil if it is not a fixed list
+func getArgValues(cmd command) []string {
+    if cmd.name == "completion" {
+        return completionShells
+    }
+    return nil
+}
+
+// takesFileArg returns whether the argument of cmd can be a file
+func takesFileArg(cmd command) bool {
+    return strings.Contains(cmd.args, "file")
+}
+
+func getCommandNames() []string {
+    names := make([]string, len(commands))
+    for i, cmd := range commands {
+        names[i] = cmd.name
+    }
+    return names
+}
+
+// writeCompletion writes the completion script for shell
+func writeCompletion(output io.Writer, shell string) error {
+    switch shell {
+    case bashShell:
+        return writeBashCompletion(output)
+    case zshShell:
+        return writeZshCompletion(output)
+    case fishShell:
+        return writeFishCompletion(output)
+    }
+    return newUsageError("shell must be one of %q", completionShells)
+}
+
+// quoteShell quotes s for bash, zsh and fish in single quotes
+func quoteShell(s string) string {
+    return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeBashCompletion(output io.Writer) error {
+    var b strings.Builder
+    b.WriteString(`# bash completion for sugoku, generated by "sugoku completion bash"
+
+# _sugoku_values completes the current word from the newline separated values of $1
+_sugoku_values() {
+    local IFS=$'\n' value
+    COMPREPLY=()
+    for value in $1; do
+        [[ $value == "$cur"* ]] && COMPREPLY+=("$(printf '%q' "$value")")
+    done
+}
+
+_sugoku_files() {
+    compopt -o filenames 2>/dev/null
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -f -- "$cur"))
+}
+
+_sugoku() {
+    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]} cmd=play
+    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+        COMPREPLY=($(compgen -W "help `)
+    b.WriteString(strings.Join(getCommandNames(), " "))
+    b.WriteString(`" -- "$cur"))
+        return
+    elif [[ ${COMP_WORDS[1]} != -* ]]; then
+        cmd=${COMP_WORDS[1]}
+    fi
+    case $cmd in
+        help)
+            COMPREPLY=($(compgen -W "`)
+    b.WriteString(strings.Join(getCommandNames(), " "))
+    b.WriteString(`" -- "$cur"))
+            ;;
+`)
+    for _, cmd := range commands {
+        flags := getCompletionFlags(cmd)
+        fmt.Fprintf(&b, "        %s)\n            case $prev in\n", cmd.name)
+        for _, f := range flags {
+            if f.isBool {
+                continue
+            }
+            if f.files {
+                fmt.Fprintf(&b, "                -%s) _sugoku_files; return ;;\n", f.name)
+            } else if f.values != nil {
+                fmt.Fprintf(&b, "                -%s) _sugoku_values %s; return ;;\n", f.name, quoteShell(strings.Join(f.values, "\n")))
+            } else {
+                fmt.Fprintf(&b, "                -%s) return ;;\n", f.name)
+            }
+        }
+        b.WriteString("            esac\n            if [[ $cur == -* ]]; then\n")
+        names := make([]string, len(flags))
+        for i, f := range flags {
+            names[i] = "-" + f.name
+        }
+        fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", quoteShell(strings.Join(names, " ")))
+        if values := getArgValues(cmd); values != nil {
+            fmt.Fprintf(&b, "            else\n                COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", quoteShell(strings.Join(values, " ")))
+        } else if takesFileArg(cmd) {
+            b.WriteString("            else\n                _sugoku_files\n")
+        }
+        b.WriteString("            fi\n            ;;\n")
+    }
+    b.WriteString(`    esac
+}
+
+complete -F _sugoku sugoku
+`)
+    _, err := io.WriteString(output, b.String())
+    return err
+}
+
+// escapeZsh escapes the characters _arguments and _describe treat specially in descriptions
+func escapeZsh(s string) string {
+    return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+}
+
+func writeZshCompletion(output io.Writer) error {
+    var b strings.Builder
+    b.WriteString(`#compdef sugoku
+# zsh completion for sugoku, generated by "sugoku completion zsh"
+
+_sugoku() {
+    local -a commands
+    commands=(
+`)
+    for _, cmd := range commands {
+        fmt.Fprintf(&b, "        %s\n", quoteShell(cmd.name+":"+escapeZsh(cmd.description)))
+    }
+    b.WriteString(`    )
+    local cmd=play
+    if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then
+        _describe -t commands 'sugoku command' commands
+        return
+    elif [[ $words[2] != -* ]]; then
+        cmd=$words[2]
+        shift words
+        (( CURRENT-- ))
+    fi
+    case $cmd in
+        help)
+            _describe -t commands 'sugoku command' commands
+            ;;
+`)
+    for _, cmd := range commands {
+        fmt.Fprintf(&b, "        %s)\n            _arguments", cmd.name)
+        for _, f := range getCompletionFlags(cmd) {
+            spec := fmt.Sprintf("-%s[%s]", f.name, escapeZsh(f.usage))
+            if f.files {
+                spec += fmt.Sprintf(":%s:_files", f.valueName)
+            } else if f.values != nil {
+                values := make([]string, len(f.values))
+                for i, value := range f.values {
+                    values[i] = strings.ReplaceAll(value, " ", `\ `)
+                }
+                spec += fmt.Sprintf(":%s:(%s)", f.valueName, strings.Join(values, " "))
+            } else if !f.isBool {
+                spec += fmt.Sprintf(":%s: ", f.valueName)
+            }
+            fmt.Fprintf(&b, " \\\n                %s", quoteShell(spec))
+        }
+        if values := getArgValues(cmd); values != nil {
+            fmt.Fprintf(&b, " \\\n                %s", quoteShell(fmt.Sprintf("1:%s:(%s)", "shell", strings.Join(values, " "))))
+        } else if takesFileArg(cmd) {
+            fmt.Fprintf(&b, " \\\n                %s", quoteShell("1:file:_files"))
+        } else if cmd.args != "" {
+            fmt.Fprintf(&b, " \\\n                %s", quoteShell(fmt.Sprintf("1:%s: ", strings.Trim(cmd.args, "<>"))))
+        }
+        b.WriteString("\n            ;;\n")
+    }
+    b.WriteString(`    esac
+}
+
+_sugoku "$@"
+`)
+    _, err := io.WriteString(output, b.String())
+    return err
+}
+
+func writeFishCompletion(output io.Writer) error {
+    var b strings.Builder
+    b.WriteString("# fish completion for sugoku, generated by \"sugoku completion fish\"\n\ncomplete -c sugoku -f\n")
+    for _, cmd := range append([]command{{name: "help", description: "print the usage of sugoku or a command"}}, commands...) {
+        fmt.Fprintf(&b, "complete -c sugoku -n __fish_use_subcommand -a %s -d %s\n", cmd.name, quoteShell(cmd.description))
+    }
+    fmt.Fprintf(&b, "complete -c sugoku -n '__fish_seen_subcommand_from help' -a %s\n", quoteShell(strings.Join(getCommandNames(), " ")))
+    for _, cmd := range commands {
+        condition := quoteShell("__fish_seen_subcommand_from " + cmd.name)
+        if cmd.name == defaultCommand {
+            condition = quoteShell("__fish_use_subcommand; or __fish_seen_subcommand_from " + cmd.name)
+        }
+        b.WriteString("\n")
+        for _, f := range getCompletionFlags(cmd) {
+            fmt.Fprintf(&b, "complete -c sugoku -n %s -o %s -d %s", condition, f.name, quoteShell(f.usage))
+            if f.files {
+                b.WriteString(" -r -F")
+            } else if f.values != nil {
+                values := make([]string, len(f.values))
+                for i, value := range f.values {
+                    values[i] = quoteShell(value)
+                }
+                fmt.Fprintf(&b, " -x -a %s", quoteShell(strings.Join(values, " ")))
+            } else if !f.isBool {
+                b.WriteString(" -x")
+            }
+            b.WriteString("\n")
+        }
+        if values := getArgValues(cmd); values != nil {
+            fmt.Fprintf(&b, "complete -c sugoku -n %s -a %s\n", condition, quoteShell(strings.Join(values, " ")))
+        } else if takesFileArg(cmd) {
+            fmt.Fprintf(&b, "complete -c sugoku -n %s -F\n", condition)
+        }
+    }
+    _, err := io.WriteString(output, b.String())
+    return err
+}
+
+func completionCommand(flags *flag.FlagSet) commandRunner {
+    return func(args []string) error {
+        shell, err := getCommandArg(flags, args)
+        if err != nil {
+            return err
+        }
+        return writeCompletion(os.Stdout, shell)
+    }
+}
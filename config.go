@@ -0,0 +1,294 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "slices"
+    "strconv"
+    "strings"
+
+    "github.com/charmbracelet/lipgloss"
+)
+
+const (
+    themeSection = "theme"
+    keysSection  = "keys"
+)
+
+// configValue is a string, integer or boolean of the config file as text, or an array of them
+type configValue struct {
+    values []string
+    array  bool
+    line   int
+}
+
+// config maps the sections of the config file to their keys, the keys before the first section
+// are in the section ""
+type config map[string]map[string]configValue
+
+// getConfigPath returns $SUGOKU_CONFIG or config.toml in the sugoku directory of the user's config
+// directory, e.g. ~/.config/sugoku/config.toml
+func getConfigPath() (string, error) {
+    if path := os.Getenv("SUGOKU_CONFIG"); path != "" {
+        return path, nil
+    }
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "sugoku", "config.toml"), nil
+}
+
+// loadConfig reads the config file and returns it with its path, the file is optional,
+// so a missing file is an empty config
+func loadConfig() (config, string, error) {
+    path, err := getConfigPath()
+    if err != nil {
+        return config{}, "", nil
+    }
+    file, err := os.Open(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return config{}, path, nil
+    } else if err != nil {
+        return nil, path, err
+    }
+    defer file.Close()
+    c, err := parseConfig(file)
+    return c, path, err
+}
+
+// parseConfig parses the subset of TOML needed for the config: sections, comments and keys with strings,
+// integers, booleans or single line arrays of them as values
+func parseConfig(reader io.Reader) (config, error) {
+    c := config{"": {}}
+    section := ""
+    scanner := bufio.NewScanner(reader)
+    for lineNumber := 1; scanner.Scan(); lineNumber++ {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        if strings.HasPrefix(line, "[") {
+            end := strings.Index(line, "]")
+            if end == -1 || !isConfigComment(line[end+1:]) {
+                return nil, newUsageError("line %d: invalid section %q", lineNumber, line)
+            }
+            section = strings.TrimSpace(line[1:end])
+            if _, ok := c[section]; ok || section == "" {
+                return nil, newUsageError("line %d: duplicate or empty section %q", lineNumber, section)
+            }
+            c[section] = map[string]configValue{}
+            continue
+        }
+        name, rest, ok := strings.Cut(line, "=")
+        name = strings.TrimSpace(name)
+        if !ok || name == "" || strings.ContainsAny(name, " \t\"'") {
+            return nil, newUsageError("line %d: expected key = value, got %q", lineNumber, line)
+        } else if _, ok := c[section][name]; ok {
+            return nil, newUsageError("line %d: duplicate key %q", lineNumber, name)
+        }
+        value, err := parseConfigValue(strings.TrimSpace(rest))
+        if err != nil {
+            return nil, newUsageError("line %d: %v", lineNumber, err)
+        }
+        value.line = lineNumber
+        c[section][name] = value
+    }
+    return c, scanner.Err()
+}
+
+func isConfigComment(s string) bool {
+    s = strings.TrimSpace(s)
+    return s == "" || strings.HasPrefix(s, "#")
+}
+
+// parseConfigValue parses a scalar or an array of scalars followed by an optional comment
+func parseConfigValue(s string) (configValue, error) {
+    var value configValue
+    if !strings.HasPrefix(s, "[") {
+        scalar, rest, err := parseConfigScalar(s)
+        if err != nil {
+            return value, err
+        } else if !isConfigComment(rest) {
+            return value, fmt.Errorf("unexpected %q after value", rest)
+        }
+        value.values = []string{scalar}
+        return value, nil
+    }
+    value.array = true
+    s = strings.TrimSpace(s[1:])
+    for !strings.HasPrefix(s, "]") {
+        scalar, rest, err := parseConfigScalar(s)
+        if err != nil {
+            return value, err
+        }
+        value.values = append(value.values, scalar)
+        s = strings.TrimSpace(rest)
+        if strings.HasPrefix(s, ",") {
+            s = strings.TrimSpace(s[1:])
+        } else if !strings.HasPrefix(s, "]") {
+            return value, fmt.Errorf("expected , or ] in array, got %q", s)
+        }
+    }
+    if !isConfigComment(s[1:]) {
+        return value, fmt.Errorf("unexpected %q after array", s[1:])
+    }
+    return value, nil
+}
+
+// parseConfigScalar parses the string, integer or boolean at the start of s and returns it as text
+// together with the rest of s
+func parseConfigScalar(s string) (string, string, error) {
+    switch {
+    case strings.HasPrefix(s, `"`):
+        for end := 1; end < len(s); end++ {
+            if s[end] == '\\' {
+                end++
+            } else if s[end] == '"' {
+                value, err := strconv.Unquote(s[:end+1])
+                if err != nil {
+                    return "", "", fmt.Errorf("invalid string %s", s[:end+1])
+                }
+                return value, s[end+1:], nil
+            }
+        }
+        return "", "", fmt.Errorf("unterminated string %s", s)
+    case strings.HasPrefix(s, "'"):
+        value, rest, ok := strings.Cut(s[1:], "'")
+        if !ok {
+            return "", "", fmt.Errorf("unterminated string %s", s)
+        }
+        return value, rest, nil
+    }
+    end := strings.IndexAny(s, ",]# \t")
+    if end == -1 {
+        end = len(s)
+    }
+    value := s[:end]
+    if value == "true" || value == "false" {
+        return value, s[end:], nil
+    } else if _, err := strconv.Atoi(value); err == nil && value != "" {
+        return value, s[end:], nil
+    }
+    return "", "", fmt.Errorf("invalid value %q, strings need quotes", value)
+}
+
+// applyConfig loads the config and sets the defaults of the flags of cmd from the keys before any section,
+// which apply to all commands with such a flag, and then from the section of cmd, flags given on the
+// command line are parsed afterwards and override them, the theme and keys sections configure the TUI
+func applyConfig(flags *flag.FlagSet, cmd command) error {
+    c, path, err := loadConfig()
+    if err == nil {
+        err = c.apply(flags, cmd)
+    }
+    if err != nil {
+        return fmt.Errorf("config %s: %w", path, err)
+    }
+    return nil
+}
+
+func (c config) apply(flags *flag.FlagSet, cmd command) error {
+    for section := range c {
+        if _, ok := findCommand(section); !ok && section != "" && section != themeSection && section != keysSection {
+            return newUsageError("unknown section %q", section)
+        }
+    }
+    for _, section := range []string{"", cmd.name} {
+        for _, name := range getSortedConfigKeys(c[section]) {
+            value := c[section][name]
+            if flags.Lookup(name) == nil {
+                if section == "" && isAnyCommandFlag(name) {
+                    continue
+                }
+                return newUsageError("line %d: unknown flag %q", value.line, name)
+            } else if value.array {
+                return newUsageError("line %d: flag %q takes a single value", value.line, name)
+            }
+            if err := setFlagDefault(flags.Lookup(name), value.values[0]); err != nil {
+                return newUsageError("line %d: invalid value for flag %q: %v", value.line, name, err)
+            }
+        }
+    }
+    if err := applyThemeConfig(c[themeSection]); err != nil {
+        return err
+    }
+    return applyKeysConfig(c[keysSection])
+}
+
+// setFlagDefault sets f to value and shows it as the default in the usage, unlike flags.Set it does not
+// mark f as set, so only flags given on the command line count as set
+func setFlagDefault(f *flag.Flag, value string) error {
+    if err := f.Value.Set(value); err != nil {
+        return err
+    }
+    f.DefValue = f.Value.String()
+    return nil
+}
+
+func getSortedConfigKeys(values map[string]configValue) []string {
+    var names []string
+    for name := range values {
+        names = append(names, name)
+    }
+    slices.Sort(names)
+    return names
+}
+
+// isAnyCommandFlag returns whether any command has a flag called name
+func isAnyCommandFlag(name string) bool {
+    for _, cmd := range commands {
+        if flags, _ := newCommandFlags(cmd); flags.Lookup(name) != nil {
+            return true
+        }
+    }
+    return false
+}
+
+// isValidColor returns whether color is an ANSI color number or a hex color like "#ff8800"
+func isValidColor(color string) bool {
+    if number, err := strconv.Atoi(color); err == nil {
+        return number >= 0 && number <= 255
+    }
+    if len(color) == 7 && strings.HasPrefix(color, "#") {
+        _, err := strconv.ParseUint(color[1:], 16, 32)
+        return err == nil
+    }
+    return false
+}
+
+func applyThemeConfig(values map[string]configValue) error {
+    colors := getThemeColors()
+    for _, name := range getSortedConfigKeys(values) {
+        value := values[name]
+        color, ok := colors[name]
+        if !ok {
+            return newUsageError("line %d: unknown color %q", value.line, name)
+        } else if value.array || !isValidColor(value.values[0]) {
+            return newUsageError("line %d: color %q must be an ANSI color number or a hex color like \"#ff8800\"", value.line, name)
+        }
+        *color = lipgloss.Color(value.values[0])
+    }
+    return nil
+}
+
+func applyKeysConfig(values map[string]configValue) error {
+    bindings := keys.getConfigurableBindings()
+    for _, name := range getSortedConfigKeys(values) {
+        value := values[name]
+        binding, ok := bindings[name]
+        if !ok {
+            return newUsageError("line %d: unknown key binding %q", value.line, name)
+        } else if slices.Contains(value.values, "") || len(value.values) == 0 {
+            return newUsageError("line %d: key binding %q needs at least one key", value.line, name)
+        }
+        binding.SetKeys(value.values...)
+        binding.SetHelp(strings.Join(value.values, "/"), binding.Help().Desc)
+    }
+    return nil
+}
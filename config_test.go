@@ -0,0 +1,94 @@
+package main
+
+import (
+    "errors"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestParseConfig(t *testing.T) {
+    tests := []struct {
+        name string
+        text string
+        want config
+    }{
+        {"empty", "", config{"": {}}},
+        {"comments and blank lines", "# comment\n\n   # indented comment\n", config{"": {}}},
+        {"scalars", "difficulty = 3\nsymmetric = true\nmethod = \"dig\"\ntheme = 'dark'\n", config{"": {
+            "difficulty": {values: []string{"3"}, line: 1},
+            "symmetric":  {values: []string{"true"}, line: 2},
+            "method":     {values: []string{"dig"}, line: 3},
+            "theme":      {values: []string{"dark"}, line: 4},
+        }}},
+        {"negative integer", "cores = -1", config{"": {"cores": {values: []string{"-1"}, line: 1}}}},
+        {"escapes in strings", `text = "a \"quoted\" # word"`, config{"": {
+            "text": {values: []string{`a "quoted" # word`}, line: 1},
+        }}},
+        {"trailing comments", "difficulty = 2 # medium\n[generate] # section\nseed = 7", config{
+            "":         {"difficulty": {values: []string{"2"}, line: 1}},
+            "generate": {"seed": {values: []string{"7"}, line: 3}},
+        }},
+        {"arrays", "up = [\"k\", 'w', 1, true]\nempty = []\ntrailing = [1, 2,]", config{"": {
+            "up":       {values: []string{"k", "w", "1", "true"}, array: true, line: 1},
+            "empty":    {array: true, line: 2},
+            "trailing": {values: []string{"1", "2"}, array: true, line: 3},
+        }}},
+        {"same key in different sections", "seed = 1\n[play]\nseed = 2\n[generate]\nseed = 3", config{
+            "":         {"seed": {values: []string{"1"}, line: 1}},
+            "play":     {"seed": {values: []string{"2"}, line: 3}},
+            "generate": {"seed": {values: []string{"3"}, line: 5}},
+        }},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            got, err := parseConfig(strings.NewReader(test.text))
+            if err != nil {
+                t.Fatalf("could not parse config: %v", err)
+            }
+            if !reflect.DeepEqual(got, test.want) {
+                t.Errorf("config is %+v, want %+v", got, test.want)
+            }
+        })
+    }
+}
+
+func TestParseConfigRejectsInvalidConfigs(t *testing.T) {
+    tests := []struct {
+        name string
+        text string
+    }{
+        {"duplicate key", "seed = 1\nseed = 2"},
+        {"duplicate key in section", "[play]\nseed = 1\nseed = 2"},
+        {"duplicate section", "[play]\n[play]"},
+        {"empty section", "[]"},
+        {"unterminated section", "[play"},
+        {"text after section", "[play] seed = 1"},
+        {"missing value separator", "seed 1"},
+        {"missing key", "= 1"},
+        {"key with spaces", "random seed = 1"},
+        {"unquoted string", "method = dig"},
+        {"empty value", "seed ="},
+        {"unterminated string", "method = \"dig"},
+        {"unterminated single quoted string", "method = 'dig"},
+        {"unterminated escaped string", `method = "dig\"`},
+        {"invalid escape", `method = "\q"`},
+        {"text after value", "seed = 1 2"},
+        {"unterminated array", "up = [\"k\", \"w\""},
+        {"unterminated array after comma", "up = [\"k\","},
+        {"missing comma in array", "up = [\"k\" \"w\"]"},
+        {"text after array", "up = [\"k\"] \"w\""},
+        {"nested array", "up = [[\"k\"]]"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            _, err := parseConfig(strings.NewReader(test.text))
+            var usageError *UsageError
+            if err == nil {
+                t.Errorf("parsing %q succeeded, want an error", test.text)
+            } else if !errors.As(err, &usageError) {
+                t.Errorf("parsing %q returned %v, want a usage error", test.text, err)
+            }
+        })
+    }
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// exit codes of the CLI for each class of failure
+const (
+    exitFailure       = 1 // any other failure, e.g. validated puzzles that are invalid
+    exitUsage         = 2 // invalid flags or arguments
+    exitInvalidPuzzle = 3 // a given puzzle cannot be parsed or does not have exactly one solution
+    exitIO            = 4 // a file cannot be read or written
+    exitInternal      = 5 // the generator or solver reached an invalid state
+)
+
+// UsageError reports invalid flags or arguments
+type UsageError struct {
+    reason string
+}
+
+func (err *UsageError) Error() string {
+    return err.reason
+}
+
+func newUsageError(format string, args ...any) error {
+    return &UsageError{fmt.Sprintf(format, args...)}
+}
+
+// PuzzleError reports a given puzzle that cannot be read, boards that cannot be used
+// as puzzles are reported by the engine as sudoku.PuzzleError
+type PuzzleError struct {
+    reason string
+}
+
+func (err *PuzzleError) Error() string {
+    return err.reason
+}
+
+func newPuzzleError(format string, args ...any) error {
+    return &PuzzleError{fmt.Sprintf(format, args...)}
+}
+
+var errAlreadySolved = &PuzzleError{"puzzle is already solved"}
+
+func getExitCode(err error) int {
+    var usageErr *UsageError
+    var puzzleErr *PuzzleError
+    var boardErr *sudoku.PuzzleError
+    var internalErr *sudoku.InternalError
+    var optionsErr *sudoku.OptionsError
+    var pathErr *fs.PathError
+    switch {
+    case errors.As(err, &usageErr), errors.As(err, &optionsErr):
+        return exitUsage
+    case errors.As(err, &puzzleErr), errors.As(err, &boardErr):
+        return exitInvalidPuzzle
+    case errors.As(err, &internalErr):
+        return exitInternal
+    case errors.As(err, &pathErr):
+        return exitIO
+    }
+    return exitFailure
+}
+
+// exitWithError prints err to stderr and exits with the exit code of its class
+func exitWithError(err error) {
+    fmt.Fprintf(os.Stderr, "sugoku: %v\n", err)
+    os.Exit(getExitCode(err))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// formatStepEffect lists the digits a step places as "r1c2=3" and the candidates it removes as "r1c2<>3"
+func formatStepEffect(step sudoku.SolutionStep) string {
+    operator := "="
+    if step.EffectType == sudoku.RemoveCandidate {
+        operator = "<>"
+    }
+    effects := make([]string, len(step.TargetCells))
+    for i, cell := range step.TargetCells {
+        effects[i] = fmt.Sprintf("r%dc%d%s%d", cell[0]+1, cell[1]+1, operator, step.TargetValues[i])
+    }
+    return strings.Join(effects, ", ")
+}
+
+// runExplain prints every step the strategies take to solve game, optionally followed by the board after it
+func runExplain(game sudoku.Sudoku, boards bool) {
+    fmt.Println("Puzzle:")
+    printBoard(game.Board)
+    var path []sudoku.SolutionStep
+    var last sudoku.Sudoku
+    solved := sudoku.WalkSolvePath(&game, func(step sudoku.SolutionStep, current *sudoku.Sudoku) {
+        path = append(path, step)
+        last = *current
+        fmt.Printf("%d. %s (difficulty %d): %s\n", len(path), step.Strategy, step.Difficulty, step.Description)
+        fmt.Printf("   => %s\n", formatStepEffect(step))
+        if boards && step.EffectType == sudoku.PlaceNumber {
+            printBoard(current.Board)
+        }
+    })
+    if !solved {
+        fmt.Printf("The strategies got stuck after %d steps at:\n", len(path))
+        if len(path) == 0 {
+            last = game
+        }
+        printBoard(last.Board)
+    } else {
+        fmt.Printf("Solved in %d steps\n", len(path))
+    }
+    difficulty := sudoku.MaxDifficulty
+    if solved {
+        difficulty = sudoku.PathDifficulty(path)
+    }
+    fmt.Printf("Difficulty: %d\n", difficulty)
+    fmt.Printf("Rating: %d\n", sudoku.NumericRating(&game, path, solved))
+}
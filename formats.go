@@ -0,0 +1,118 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "unicode"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// loadPuzzles reads all puzzles of a puzzle file if puzzle is the path of one,
+// otherwise it loads a single puzzle with loadPuzzle
+func loadPuzzles(puzzle string, stdin io.Reader) ([]sudoku.Sudoku, error) {
+    if isPuzzleFile(puzzle) {
+        return readPuzzleFile(puzzle)
+    }
+    game, err := loadPuzzle(puzzle, stdin)
+    if err != nil {
+        return nil, err
+    }
+    return []sudoku.Sudoku{game}, nil
+}
+
+// loadPuzzle parses a puzzle given in the line format, or reads the first non-empty line
+// from stdin if the puzzle is "-", and makes sure it has exactly one solution
+func loadPuzzle(puzzle string, stdin io.Reader) (sudoku.Sudoku, error) {
+    board, err := loadBoard(puzzle, stdin)
+    if err != nil {
+        return sudoku.Sudoku{}, err
+    }
+    if sudoku.IsSolved(board) {
+        return sudoku.Sudoku{}, errAlreadySolved
+    }
+    return sudoku.New(board)
+}
+
+// loadBoard parses a board given in the line format, or read from the first non-empty line
+// of stdin if the puzzle is "-", without checking it
+func loadBoard(puzzle string, stdin io.Reader) ([9][9]uint8, error) {
+    if puzzle == "-" {
+        puzzle = ""
+        scanner := bufio.NewScanner(stdin)
+        for puzzle == "" && scanner.Scan() {
+            puzzle = strings.TrimSpace(scanner.Text())
+        }
+        if err := scanner.Err(); err != nil {
+            return [9][9]uint8{}, err
+        } else if puzzle == "" {
+            return [9][9]uint8{}, newPuzzleError("no puzzle on stdin")
+        }
+    }
+    return sudoku.ParseBoard(puzzle)
+}
+
+// loadBoards is like loadPuzzles, but returns the boards without checking them
+func loadBoards(puzzle string, stdin io.Reader) ([][9][9]uint8, error) {
+    if isPuzzleFile(puzzle) {
+        return readPuzzleBoards(puzzle)
+    }
+    board, err := loadBoard(puzzle, stdin)
+    if err != nil {
+        return nil, err
+    }
+    return [][9][9]uint8{board}, nil
+}
+
+// parseMask parses a clue mask given as 81 characters of 'x' for clues and '.' for empty cells,
+// whitespace is ignored so that the mask can also span 9 lines
+func parseMask(text string) ([9][9]bool, error) {
+    var mask [9][9]bool
+    idx := 0
+    for _, char := range text {
+        if unicode.IsSpace(char) {
+            continue
+        }
+        if idx >= 81 {
+            return mask, fmt.Errorf("mask has more than 81 cells")
+        }
+        switch char {
+        case 'x', 'X':
+            mask[idx/9][idx%9] = true
+        case '.':
+        default:
+            return mask, fmt.Errorf("invalid character %q in mask, expected 'x' or '.'", char)
+        }
+        idx++
+    }
+    if idx != 81 {
+        return mask, fmt.Errorf("mask has %d cells, expected 81", idx)
+    }
+    return mask, nil
+}
+
+// loadMask reads the mask from the file at path if it exists and parses path itself otherwise
+func loadMask(path string) ([9][9]bool, error) {
+    text := path
+    if content, err := os.ReadFile(path); err == nil {
+        text = string(content)
+    }
+    return parseMask(text)
+}
+
+func maskToString(mask [9][9]bool) string {
+    builder := new(strings.Builder)
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if mask[i][j] {
+                builder.WriteByte('x')
+            } else {
+                builder.WriteByte('.')
+            }
+        }
+    }
+    return builder.String()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+    "encoding/json"
+    "io"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// version of the JSON schema, to be increased on incompatible changes
+const jsonSchemaVersion = 1
+
+type jsonTarget struct {
+    Row    int `json:"row"`
+    Column int `json:"column"`
+    Value  int `json:"value"`
+}
+
+type jsonStep struct {
+    Strategy    string       `json:"strategy"`
+    Difficulty  int          `json:"difficulty"`
+    Description string       `json:"description"`
+    Effect      string       `json:"effect"`
+    Targets     []jsonTarget `json:"targets"`
+}
+
+type jsonSudoku struct {
+    SchemaVersion int        `json:"schemaVersion"`
+    Puzzle        string     `json:"puzzle"`
+    Board         [9][9]int  `json:"board"`
+    Solution      [9][9]int  `json:"solution"`
+    Givens        [9][9]bool `json:"givens"`
+    Clues         int        `json:"clues"`
+    Minimal       bool       `json:"minimal"`
+    Difficulty    int        `json:"difficulty"`
+    Rating        int        `json:"rating"`
+    Seed          *int       `json:"seed"`
+    Solved        bool       `json:"solved"`
+    SolvePath     []jsonStep `json:"solvePath"`
+}
+
+func getJsonEffect(effect sudoku.Effect) string {
+    switch effect {
+    case sudoku.PlaceNumber:
+        return "placeNumber"
+    case sudoku.RemoveCandidate:
+        return "removeCandidate"
+    }
+    return "unknown"
+}
+
+func getJsonSteps(steps []sudoku.SolutionStep) []jsonStep {
+    jsonSteps := []jsonStep{}
+    for _, step := range steps {
+        targets := []jsonTarget{}
+        for i, cell := range step.TargetCells {
+            targets = append(targets, jsonTarget{
+                Row:    cell[0] + 1,
+                Column: cell[1] + 1,
+                Value:  int(step.TargetValues[i]),
+            })
+        }
+        jsonSteps = append(jsonSteps, jsonStep{
+            Strategy:    step.Strategy,
+            Difficulty:  step.Difficulty,
+            Description: step.Description,
+            Effect:      getJsonEffect(step.EffectType),
+            Targets:     targets,
+        })
+    }
+    return jsonSteps
+}
+
+// getJsonSudoku describes a puzzle for other programs, seed is nil for puzzles that were not generated
+func getJsonSudoku(game sudoku.Sudoku, seed *int) jsonSudoku {
+    path, solved := sudoku.SolvePath(&game)
+    difficulty := sudoku.MaxDifficulty
+    if solved {
+        difficulty = sudoku.PathDifficulty(path)
+    }
+    output := jsonSudoku{
+        SchemaVersion: jsonSchemaVersion,
+        Puzzle:        game.Board.String(),
+        Clues:         sudoku.CountClues(game.Board),
+        Minimal:       sudoku.IsMinimal(game),
+        Difficulty:    difficulty,
+        Rating:        sudoku.NumericRating(&game, path, solved),
+        Seed:          seed,
+        Solved:        solved,
+        SolvePath:     getJsonSteps(path),
+    }
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            output.Board[i][j] = int(game.Board[i][j])
+            output.Solution[i][j] = int(game.Solution[i][j])
+            output.Givens[i][j] = game.Board[i][j] != 0
+        }
+    }
+    return output
+}
+
+// writeJsonSudoku writes the description of a puzzle as a single line of JSON
+func writeJsonSudoku(output io.Writer, game sudoku.Sudoku, seed *int) error {
+    return json.NewEncoder(output).Encode(getJsonSudoku(game, seed))
+}
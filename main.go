@@ -1,16 +1,24 @@
 package main
 
 import (
-    "flag"
+    "context"
     "fmt"
-    "log"
+    "math"
     "math/rand/v2"
     "os"
-    "runtime/pprof"
-    "slices"
     "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+const (
+    textFormat = "text"
+    jsonFormat = "json"
+    lineFormat = "line"
 )
 
+var outputFormats = []string{textFormat, jsonFormat}
+
 func printBoard(board [9][9]uint8) {
     leftPad := "   "
     hPad := " "
@@ -58,50 +66,61 @@ func printBoard(board [9][9]uint8) {
     fmt.Println(builder.String())
 }
 
-func runPrint(difficulty int, seed int, cores int) {
-    sudoku := generateSudokuParallel(difficulty, seed, cores)
-    println("Generated Sudoku:")
-    printBoard(sudoku.board)
-    println("Solution:")
-    printBoard(sudoku.solution)
-}
-
-func main() {
-    var (
-        cpuprofile = flag.String("cpuprofile", "", "write cpu profile to `file`")
-        print      = flag.Bool("print", false, "print a generated sudoku and its solution and exit")
-        seed       = flag.Int("seed", -1, "seed for random number generator, -1 for random seed")
-        cores      = flag.Int("cores", -1, "number of cores to use, -1 for all cores")
-        difficulty = flag.Int("difficulty", 0, "difficulty of the generated sudoku, 0 for random difficulty (default 0)")
-    )
-    flag.Usage = func() {
-        fmt.Fprintf(flag.CommandLine.Output(),
-            "Usage: sugoku [-difficulty <0-5>] [-print] [-cores <int>] [-seed <int>] [-cpuprofile <file>]\n")
-        flag.PrintDefaults()
+func runPrint(games []sudoku.Sudoku, options sudoku.GeneratorOptions, seed int, cores int, format string) error {
+    if format == jsonFormat {
+        return runPrintJson(games, options, seed, cores)
     }
-    flag.Parse()
-
-    if *cpuprofile != "" {
-        f, err := os.Create(*cpuprofile)
+    if len(games) == 0 {
+        game, err := sudoku.Generate(context.Background(), options, seed, cores)
         if err != nil {
-            log.Fatal("could not create CPU profile: ", err)
+            return err
         }
-        defer f.Close()
-        if err := pprof.StartCPUProfile(f); err != nil {
-            log.Fatal("could not start CPU profile: ", err)
+        println("Generated Sudoku:")
+        printSudoku(game)
+        return nil
+    }
+    for i, game := range games {
+        if i > 0 {
+            println()
         }
-        defer pprof.StopCPUProfile()
+        println("Sudoku:")
+        printSudoku(game)
     }
+    return nil
+}
 
-    if !slices.Contains(validDifficulties, *difficulty) {
-        log.Fatal("difficulty must be between 0 and 3")
-    } else if *difficulty == 0 {
-        *difficulty = validDifficulties[rand.IntN(len(validDifficulties)-1)+1]
+// runPrintJson writes one line of JSON per puzzle, the seed of generated puzzles is
+// chosen here if it is random, so that it can be included in the output
+func runPrintJson(games []sudoku.Sudoku, options sudoku.GeneratorOptions, seed int, cores int) error {
+    if len(games) == 0 {
+        if seed == -1 {
+            seed = rand.IntN(math.MaxInt32)
+        }
+        game, err := sudoku.Generate(context.Background(), options, seed, cores)
+        if err != nil {
+            return err
+        }
+        if err := writeJsonSudoku(os.Stdout, game, &seed); err != nil {
+            return fmt.Errorf("could not write JSON: %w", err)
+        }
+        return nil
     }
+    return writeJsonSudokus(games)
+}
 
-    if *print {
-        runPrint(*difficulty, *seed, *cores)
-    } else {
-        runTui(*difficulty, *seed, *cores)
+func printSudoku(game sudoku.Sudoku) {
+    printBoard(game.Board)
+    println("Solution:")
+    printBoard(game.Solution)
+    fmt.Printf("Difficulty: %d\n", sudoku.Rate(&game))
+    minimality := "not minimal"
+    if sudoku.IsMinimal(game) {
+        minimality = "minimal"
     }
+    fmt.Printf("Clues: %d (%s)\n", sudoku.CountClues(game.Board), minimality)
+    fmt.Printf("Line: %s\n", game.Board.String())
+}
+
+func main() {
+    runCommand(os.Args[1:])
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// exitStatuses describe the exit codes of the CLI for the man page
+var exitStatuses = []struct {
+    code        int
+    description string
+}{
+    {0, "success"},
+    {exitFailure, "any other failure, e.g. validated puzzles that are invalid"},
+    {exitUsage, "invalid flags or arguments"},
+    {exitInvalidPuzzle, "a given puzzle cannot be parsed or does not have exactly one solution"},
+    {exitIO, "a file cannot be read or written"},
+    {exitInternal, "the generator or solver reached an invalid state"},
+}
+
+// escapeRoff escapes backslashes and dashes of s and keeps lines from being read as requests
+func escapeRoff(s string) string {
+    s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+    if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+        s = `\&` + s
+    }
+    return s
+}
+
+// writeManPage writes the man page of sugoku in roff format with the commands and flags as they are defined
+func writeManPage(output io.Writer) error {
+    var b strings.Builder
+    b.WriteString(".TH SUGOKU 1\n.SH NAME\nsugoku \\- generate, solve, rate and play sudokus in the terminal\n")
+    b.WriteString(".SH SYNOPSIS\n.B sugoku\n[\\fIcommand\\fR] [\\fIflags\\fR] [\\fIarguments\\fR]\n")
+    fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", escapeRoff("sugoku generates sudokus of a given difficulty, "+
+        "rates them by the strategies needed to solve them and lets you play them in the terminal. "+
+        "Without a command it runs "+defaultCommand+"."))
+    b.WriteString(".PP\nFlags have to come before the arguments of a command.\n")
+
+    b.WriteString(".SH COMMANDS\n")
+    for _, cmd := range commands {
+        fmt.Fprintf(&b, ".SS %s\n.B sugoku %s\n[\\fIflags\\fR]", cmd.name, cmd.name)
+        if cmd.args != "" {
+            fmt.Fprintf(&b, " \\fI%s\\fR", escapeRoff(cmd.args))
+        }
+        fmt.Fprintf(&b, "\n.PP\n%s\n", escapeRoff(cmd.description))
+        for _, f := range getCompletionFlags(cmd) {
+            fmt.Fprintf(&b, ".TP\n.B \\-%s", escapeRoff(f.name))
+            if !f.isBool && f.valueName != "" {
+                fmt.Fprintf(&b, " \\fI%s\\fR", escapeRoff(f.valueName))
+            }
+            usage := f.usage
+            if f.defValue != "" && f.defValue != "0" && f.defValue != "false" {
+                usage += fmt.Sprintf(" (default %s)", f.defValue)
+            }
+            if f.values != nil {
+                usage += fmt.Sprintf(", one of: %s", strings.Join(f.values, ", "))
+            }
+            fmt.Fprintf(&b, "\n%s\n", escapeRoff(usage))
+        }
+    }
+
+    b.WriteString(".SH STRATEGIES\nThe difficulty of a sudoku is the difficulty of the hardest strategy needed to solve it, ")
+    fmt.Fprintf(&b, "%d if the strategies cannot solve it.\n", sudoku.MaxDifficulty)
+    for _, strategy := range sudoku.Strategies() {
+        fmt.Fprintf(&b, ".TP\n.B %s\ndifficulty %d\n", escapeRoff(strategy.Name()), strategy.Difficulty())
+    }
+
+    b.WriteString(".SH FILES\n.TP\n.I ~/.config/sugoku/config.toml\n")
+    b.WriteString(escapeRoff("defaults for the flags and the colors and keys of the TUI, "+
+        "or $XDG_CONFIG_HOME/sugoku/config.toml if it is set, $SUGOKU_CONFIG overrides the path"))
+    b.WriteString("\n")
+
+    b.WriteString(".SH EXIT STATUS\n")
+    for _, status := range exitStatuses {
+        fmt.Fprintf(&b, ".TP\n.B %d\n%s\n", status.code, escapeRoff(status.description))
+    }
+    _, err := io.WriteString(output, b.String())
+    return err
+}
+
+func manCommand(flags *flag.FlagSet) commandRunner {
+    return func(args []string) error {
+        return writeManPage(os.Stdout)
+    }
+}
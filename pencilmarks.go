@@ -0,0 +1,187 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// Pencil mark grids as used by HoDoKu and SudokuWiki list the candidates of each cell,
+// a cell with a single digit is considered solved:
+//
+//  .----------------.----------------.----------------.
+//  | 6    7    24   | 145  3    12   | 8    9    15   |
+//  ...
+//  :----------------+----------------+----------------:
+//  ...
+//  '----------------'----------------'----------------'
+//
+// Border lines, which contain '-', '+' or '*' and otherwise only border characters, and blank lines
+// are skipped, all other lines have to contain
+// the 9 cells of a row separated by whitespace and '|'. Empty cells without candidates are
+// written as '.'.
+
+// parsePencilMarks parses a pencil mark grid into a game whose candidates are exactly the
+// given ones, the solution is computed from the solved cells
+func parsePencilMarks(text string) (sudoku.Sudoku, error) {
+    var game sudoku.Sudoku
+    row := 0
+    for _, line := range strings.Split(text, "\n") {
+        if strings.TrimSpace(line) == "" || isPencilMarkBorder(line) {
+            continue
+        }
+        if row == 9 {
+            return game, newPuzzleError("grid has more than 9 rows")
+        }
+        cells := strings.Fields(strings.ReplaceAll(line, "|", " "))
+        if len(cells) != 9 {
+            return game, newPuzzleError("row %d has %d cells, expected 9", row+1, len(cells))
+        }
+        for col, cell := range cells {
+            if cell == "." {
+                continue
+            }
+            for _, char := range cell {
+                if char < '1' || char > '9' {
+                    return game, newPuzzleError("invalid candidate %q in r%dc%d", char, row+1, col+1)
+                }
+                game.Candidates[row][col][char-'1'] = true
+            }
+            if len(cell) == 1 {
+                game.Board[row][col] = uint8(cell[0] - '0')
+                game.Candidates[row][col] = [9]bool{}
+            }
+        }
+        row++
+    }
+    if row != 9 {
+        return game, newPuzzleError("grid has %d rows, expected 9", row)
+    }
+    countCandidates(&game)
+    solvedGame, err := sudoku.New(game.Board)
+    if err != nil {
+        return game, err
+    }
+    game.Solution = solvedGame.Solution
+    return game, nil
+}
+
+// isPencilMarkBorder returns whether line is a border of a pencil mark grid, a row of empty cells
+// consists of '.' and '|' as well, but never contains '-', '+' or '*'
+func isPencilMarkBorder(line string) bool {
+    return strings.ContainsAny(line, "-+*") && strings.Trim(line, ".:'-+*| \t\r") == ""
+}
+
+// countCandidates sets the candidate counts of game to the number of its candidates,
+// e.g. after the candidates were replaced by pencil marks
+func countCandidates(game *sudoku.Sudoku) {
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            game.CandidatesCount[i][j] = len(sudoku.CellCandidates(game, i, j))
+        }
+    }
+}
+
+func readPencilMarksFile(path string) (sudoku.Sudoku, error) {
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return sudoku.Sudoku{}, err
+    }
+    return parsePencilMarks(string(content))
+}
+
+func getPencilMarksCell(game sudoku.Sudoku, row int, col int) string {
+    if game.Board[row][col] != 0 {
+        return fmt.Sprintf("%d", game.Board[row][col])
+    }
+    cell := ""
+    for _, candidate := range sudoku.CellCandidates(&game, row, col) {
+        cell += fmt.Sprintf("%d", candidate)
+    }
+    if cell == "" {
+        return "."
+    }
+    return cell
+}
+
+// formatPencilMarks writes the board and candidates of game as a pencil mark grid,
+// each column is as wide as its widest cell
+func formatPencilMarks(game sudoku.Sudoku) string {
+    var cells [9][9]string
+    var colWidths [9]int
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            cells[i][j] = getPencilMarksCell(game, i, j)
+            colWidths[j] = max(colWidths[j], len(cells[i][j]))
+        }
+    }
+    border := func(left string, middle string, right string) string {
+        line := left
+        for stack := 0; stack < 3; stack++ {
+            if stack > 0 {
+                line += middle
+            }
+            // a space before each cell and two after each but the last cell of the stack
+            width := 2
+            for col := 3 * stack; col < 3*stack+3; col++ {
+                width += colWidths[col] + 2
+            }
+            line += strings.Repeat("-", width-2)
+        }
+        return line + right + "\n"
+    }
+    builder := new(strings.Builder)
+    builder.WriteString(border(".", ".", "."))
+    for i := 0; i < 9; i++ {
+        if i > 0 && i%3 == 0 {
+            builder.WriteString(border(":", "+", ":"))
+        }
+        for j := 0; j < 9; j++ {
+            if j%3 == 0 {
+                builder.WriteString("| ")
+            } else {
+                builder.WriteString("  ")
+            }
+            builder.WriteString(cells[i][j] + strings.Repeat(" ", colWidths[j]-len(cells[i][j])))
+            if j%3 == 2 {
+                builder.WriteString(" ")
+            }
+        }
+        builder.WriteString("|\n")
+    }
+    builder.WriteString(border("'", "'", "'"))
+    return builder.String()
+}
+
+// exportPencilMarks writes the pencil mark grid of the user's game to a new file in the current
+// directory, empty cells without pencil marks get all candidates that are possible given the board
+func exportPencilMarks(game sudoku.Sudoku) (string, error) {
+    computedGame := game
+    sudoku.ComputeCandidates(&computedGame)
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if game.Board[i][j] == 0 && game.Candidates[i][j] == [9]bool{} {
+                game.Candidates[i][j] = computedGame.Candidates[i][j]
+            }
+        }
+    }
+    path := fmt.Sprintf("sugoku-%s.txt", time.Now().Format("20060102-150405"))
+    return path, os.WriteFile(path, []byte(formatPencilMarks(game)), 0o644)
+}
+
+// runPencilMarks prints the pencil mark grid of game and the hints for its current state
+func runPencilMarks(game sudoku.Sudoku) {
+    fmt.Print(formatPencilMarks(game))
+    steps := sudoku.Hints(&game)
+    if len(steps) == 0 {
+        fmt.Println("No hints available")
+        return
+    }
+    fmt.Printf("%s:\n", steps[0].Strategy)
+    for _, step := range steps {
+        fmt.Println(step.Description)
+    }
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// puzzlePool keeps pre-generated puzzles in the user's cache directory,
+// with one file per set of generator options, so new games can start instantly
+type puzzlePool struct {
+    dir       string
+    size      int
+    mutex     sync.Mutex
+    refilling map[string]bool
+}
+
+func newPuzzlePool(size int) (*puzzlePool, error) {
+    cacheDir, err := os.UserCacheDir()
+    if err != nil {
+        return nil, err
+    }
+    dir := filepath.Join(cacheDir, "sugoku", "pool")
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, err
+    }
+    pool := &puzzlePool{
+        dir:       dir,
+        size:      size,
+        refilling: make(map[string]bool),
+    }
+    return pool, nil
+}
+
+func getPoolKey(options sudoku.GeneratorOptions) string {
+    key := fmt.Sprintf("%s-difficulty%d", options.Method, options.Difficulty)
+    if options.Symmetric {
+        key += "-symmetric"
+    }
+    if options.Minimal {
+        key += "-minimal"
+    }
+    if options.MinClues > 0 {
+        key += fmt.Sprintf("-min%d", options.MinClues)
+    }
+    if options.MaxClues > 0 {
+        key += fmt.Sprintf("-max%d", options.MaxClues)
+    }
+    if options.RequiredStrategy != "" {
+        key += "-" + strings.ToLower(strings.ReplaceAll(options.RequiredStrategy, " ", "-"))
+    }
+    if options.RequireHardest {
+        key += "-hardest"
+    }
+    if options.Mask != nil {
+        key += "-mask" + strings.ReplaceAll(maskToString(*options.Mask), ".", "o")
+    }
+    return key
+}
+
+func (pool *puzzlePool) getPath(options sudoku.GeneratorOptions) string {
+    return filepath.Join(pool.dir, getPoolKey(options)+".txt")
+}
+
+// each line of a pool file contains a board and its solution in the 81 character line format,
+// corrupt lines are skipped, so they neither count towards the size of the pool nor are written back
+func (pool *puzzlePool) readLines(options sudoku.GeneratorOptions) []string {
+    var lines []string
+    file, err := os.Open(pool.getPath(options))
+    if err != nil {
+        return lines
+    }
+    defer file.Close()
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if _, err := parsePoolLine(line); err == nil {
+            lines = append(lines, line)
+        }
+    }
+    return lines
+}
+
+// take removes a puzzle from the pool, it returns false if there is none
+func (pool *puzzlePool) take(options sudoku.GeneratorOptions) (sudoku.Sudoku, bool) {
+    pool.mutex.Lock()
+    defer pool.mutex.Unlock()
+    lines := pool.readLines(options)
+    if len(lines) == 0 {
+        return sudoku.Sudoku{}, false
+    }
+    game, err := parsePoolLine(lines[0])
+    if err != nil {
+        return game, false
+    }
+    if err := pool.writeLines(options, lines[1:]); err != nil {
+        return game, false
+    }
+    return game, true
+}
+
+// writeLines replaces the pool file by renaming a temporary file with a unique name,
+// so that concurrent sugoku processes never write to the same file
+func (pool *puzzlePool) writeLines(options sudoku.GeneratorOptions, lines []string) error {
+    path := pool.getPath(options)
+    content := strings.Join(lines, "\n")
+    if len(lines) > 0 {
+        content += "\n"
+    }
+    file, err := os.CreateTemp(pool.dir, filepath.Base(path)+".*.tmp")
+    if err != nil {
+        return err
+    }
+    _, err = file.WriteString(content)
+    if closeErr := file.Close(); err == nil {
+        err = closeErr
+    }
+    if err == nil {
+        err = os.Chmod(file.Name(), 0o644)
+    }
+    if err == nil {
+        err = os.Rename(file.Name(), path)
+    }
+    if err != nil {
+        os.Remove(file.Name())
+    }
+    return err
+}
+
+func (pool *puzzlePool) add(options sudoku.GeneratorOptions, game sudoku.Sudoku) error {
+    pool.mutex.Lock()
+    defer pool.mutex.Unlock()
+    lines := append(pool.readLines(options), game.Board.String()+" "+game.Solution.String())
+    return pool.writeLines(options, lines)
+}
+
+// refill generates puzzles until the pool is full, it is meant to run in the background while the
+// user plays, so it only uses a single core and returns immediately if a refill is already running
+func (pool *puzzlePool) refill(options sudoku.GeneratorOptions) {
+    key := getPoolKey(options)
+    pool.mutex.Lock()
+    if pool.refilling[key] {
+        pool.mutex.Unlock()
+        return
+    }
+    pool.refilling[key] = true
+    pool.mutex.Unlock()
+    defer func() {
+        pool.mutex.Lock()
+        delete(pool.refilling, key)
+        pool.mutex.Unlock()
+    }()
+    for {
+        pool.mutex.Lock()
+        numPuzzles := len(pool.readLines(options))
+        pool.mutex.Unlock()
+        if numPuzzles >= pool.size {
+            return
+        }
+        game, err := sudoku.Generate(context.Background(), options, -1, 1)
+        if err != nil {
+            return
+        }
+        if err := pool.add(options, game); err != nil {
+            return
+        }
+    }
+}
+
+func parsePoolLine(line string) (sudoku.Sudoku, error) {
+    game := sudoku.Empty()
+    fields := strings.Fields(line)
+    if len(fields) != 2 {
+        return game, fmt.Errorf("expected board and solution, got %d fields", len(fields))
+    }
+    board, err := sudoku.ParseBoard(fields[0])
+    if err != nil {
+        return game, err
+    }
+    solution, err := sudoku.ParseBoard(fields[1])
+    if err != nil {
+        return game, err
+    }
+    if !sudoku.IsValidUnsolvedBoard(board) || !sudoku.IsValidSolvedBoard(solution) {
+        return game, fmt.Errorf("invalid board or solution")
+    }
+    game.Board = board
+    game.Solution = solution
+    sudoku.ComputeCandidates(&game)
+    return game, nil
+}
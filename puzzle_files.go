@@ -0,0 +1,218 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// Supported puzzle files:
+//   - .sdk (SadMan Sudoku): a single puzzle given as 9 rows of 9 characters with '.' for empty cells,
+//     optionally preceded by metadata lines starting with '#' and a [Puzzle] section header
+//   - .sdm (SadMan Sudoku multi): one puzzle per line in the 81 character line format
+//   - .ss (Simple Sudoku): a single puzzle given as 9 rows with '.' for empty cells, where rows,
+//     boxes and the whole grid may be separated by borders made of '|', '-', '+' and '*'
+var puzzleFileExtensions = []string{".sdk", ".sdm", ".ss"}
+
+func isPuzzleFile(path string) bool {
+    extension := strings.ToLower(filepath.Ext(path))
+    for _, supportedExtension := range puzzleFileExtensions {
+        if extension == supportedExtension {
+            return true
+        }
+    }
+    return false
+}
+
+// readPuzzleFile reads all puzzles of a puzzle file, each of which has to have exactly one solution
+func readPuzzleFile(path string) ([]sudoku.Sudoku, error) {
+    boards, err := readPuzzleBoards(path)
+    if err != nil {
+        return nil, err
+    }
+    var games []sudoku.Sudoku
+    for i, board := range boards {
+        game, err := sudoku.New(board)
+        if err != nil {
+            return nil, fmt.Errorf("puzzle %d: %w", i+1, err)
+        }
+        games = append(games, game)
+    }
+    return games, nil
+}
+
+// readPuzzleBoards reads the boards of all puzzles of a puzzle file without checking them
+func readPuzzleBoards(path string) ([][9][9]uint8, error) {
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    var boards [][9][9]uint8
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".sdk":
+        board, err := parseSdk(string(content))
+        if err != nil {
+            return nil, err
+        }
+        boards = append(boards, board)
+    case ".ss":
+        board, err := parseSs(string(content))
+        if err != nil {
+            return nil, err
+        }
+        boards = append(boards, board)
+    case ".sdm":
+        boards, err = parseSdm(string(content))
+        if err != nil {
+            return nil, err
+        }
+    default:
+        return nil, newUsageError("unsupported file extension of %s, must be one of %q", path, puzzleFileExtensions)
+    }
+    return boards, nil
+}
+
+// checkPuzzleFile returns a usage error if numPuzzles puzzles cannot be written to path
+func checkPuzzleFile(path string, numPuzzles int) error {
+    extension := strings.ToLower(filepath.Ext(path))
+    if !isPuzzleFile(path) {
+        return newUsageError("unsupported file extension of %s, must be one of %q", path, puzzleFileExtensions)
+    } else if numPuzzles < 1 {
+        return newUsageError("need at least one puzzle to write %s, got %d", path, numPuzzles)
+    } else if numPuzzles != 1 && extension != ".sdm" {
+        return newUsageError("%s files contain exactly one puzzle, got %d", extension, numPuzzles)
+    }
+    return nil
+}
+
+// writePuzzleFile writes the puzzles in the format given by the extension of path,
+// only .sdm files can contain more than one puzzle
+func writePuzzleFile(path string, games []sudoku.Sudoku) error {
+    if err := checkPuzzleFile(path, len(games)); err != nil {
+        return err
+    }
+    var content string
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".sdk":
+        content = formatSdk(games[0].Board)
+    case ".ss":
+        content = formatSs(games[0].Board)
+    case ".sdm":
+        var boards [][9][9]uint8
+        for _, game := range games {
+            boards = append(boards, game.Board)
+        }
+        content = formatSdm(boards)
+    default:
+        return newUsageError("unsupported file extension of %s, must be one of %q", path, puzzleFileExtensions)
+    }
+    return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// parseGridRows joins the first 9 rows of a grid into the line format and parses it
+func parseGridRows(rows []string) ([9][9]uint8, error) {
+    if len(rows) < 9 {
+        return [9][9]uint8{}, newPuzzleError("expected 9 rows, got %d", len(rows))
+    }
+    return sudoku.ParseBoard(strings.Join(rows[:9], ""))
+}
+
+func parseSdk(text string) ([9][9]uint8, error) {
+    var rows []string
+    for _, line := range strings.Split(text, "\n") {
+        line = strings.TrimSpace(line)
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        } else if strings.HasPrefix(line, "[") {
+            // sections after the puzzle, e.g. the solving state, are ignored
+            if len(rows) > 0 {
+                break
+            }
+            continue
+        }
+        rows = append(rows, line)
+    }
+    return parseGridRows(rows)
+}
+
+func parseSs(text string) ([9][9]uint8, error) {
+    var rows []string
+    for _, line := range strings.Split(text, "\n") {
+        row := strings.Map(func(char rune) rune {
+            if strings.ContainsRune("|-+* \t\r", char) {
+                return -1
+            }
+            return char
+        }, line)
+        if row != "" {
+            rows = append(rows, row)
+        }
+    }
+    return parseGridRows(rows)
+}
+
+func parseSdm(text string) ([][9][9]uint8, error) {
+    var boards [][9][9]uint8
+    for i, line := range strings.Split(text, "\n") {
+        line = strings.TrimSpace(line)
+        if line == "" {
+            continue
+        }
+        board, err := sudoku.ParseBoard(line)
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %w", i+1, err)
+        }
+        boards = append(boards, board)
+    }
+    if len(boards) == 0 {
+        return nil, newPuzzleError("no puzzles found")
+    }
+    return boards, nil
+}
+
+func formatGridRow(board [9][9]uint8, row int, separator string) string {
+    builder := new(strings.Builder)
+    for col := 0; col < 9; col++ {
+        if col > 0 && col%3 == 0 {
+            builder.WriteString(separator)
+        }
+        if board[row][col] == 0 {
+            builder.WriteByte('.')
+        } else {
+            builder.WriteByte('0' + board[row][col])
+        }
+    }
+    return builder.String()
+}
+
+func formatSdk(board [9][9]uint8) string {
+    builder := new(strings.Builder)
+    for row := 0; row < 9; row++ {
+        builder.WriteString(formatGridRow(board, row, "") + "\n")
+    }
+    return builder.String()
+}
+
+func formatSs(board [9][9]uint8) string {
+    builder := new(strings.Builder)
+    builder.WriteString("*-----------*\n")
+    for row := 0; row < 9; row++ {
+        if row > 0 && row%3 == 0 {
+            builder.WriteString("|---+---+---|\n")
+        }
+        builder.WriteString("|" + formatGridRow(board, row, "|") + "|\n")
+    }
+    builder.WriteString("*-----------*\n")
+    return builder.String()
+}
+
+func formatSdm(boards [][9][9]uint8) string {
+    builder := new(strings.Builder)
+    for _, board := range boards {
+        builder.WriteString(sudoku.Board(board).String() + "\n")
+    }
+    return builder.String()
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+    "image"
+    "image/color"
+    "image/draw"
+    "image/png"
+    "os"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// 5x7 bitmap glyphs of the digits 1-9, so that rendering does not depend on any font files
+var digitGlyphs = map[uint8][7]string{
+    1: {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+    2: {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+    3: {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+    4: {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+    5: {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+    6: {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+    7: {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+    8: {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+    9: {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+}
+
+// minCellSize is the smallest cell size at which the glyphs of candidates still get one pixel per glyph pixel
+const minCellSize = 36
+
+var (
+    renderBackground      = color.RGBA{255, 255, 255, 255}
+    renderLines           = color.RGBA{0, 0, 0, 255}
+    renderGiven           = color.RGBA{0, 0, 0, 255}
+    renderEntered         = color.RGBA{30, 80, 200, 255}
+    renderCandidate       = color.RGBA{110, 110, 110, 255}
+    renderSourceHighlight = color.RGBA{255, 240, 170, 255}
+    renderTargetHighlight = color.RGBA{255, 200, 200, 255}
+    renderRemoved         = color.RGBA{220, 0, 0, 255}
+    renderPlaced          = color.RGBA{0, 150, 0, 255}
+)
+
+type renderOptions struct {
+    cellSize   int
+    candidates bool
+    // highlight the source and target cells of this step, nil for no highlighting
+    step *sudoku.SolutionStep
+}
+
+// getStepSourceCells returns all cells of the contexts the step is based on
+func getStepSourceCells(step sudoku.SolutionStep) [][2]int {
+    var cells [][2]int
+    for _, contextIdx := range step.SourceIndices {
+        if step.SourceContext == sudoku.Cell {
+            row, col := sudoku.ContextCell(sudoku.Cell, contextIdx, 0)
+            cells = append(cells, [2]int{row, col})
+            continue
+        }
+        for cellIdx := range 9 {
+            row, col := sudoku.ContextCell(step.SourceContext, contextIdx, cellIdx)
+            cells = append(cells, [2]int{row, col})
+        }
+    }
+    return cells
+}
+
+func fillRect(img *image.RGBA, x0 int, y0 int, x1 int, y1 int, fill color.Color) {
+    draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+// drawDigit draws a glyph centered in the square at x, y with the given size
+func drawDigit(img *image.RGBA, digit uint8, x int, y int, size int, fill color.Color) {
+    glyph := digitGlyphs[digit]
+    pixelSize := float64(size) * 0.6 / 7
+    glyphX := float64(x) + (float64(size)-5*pixelSize)/2
+    glyphY := float64(y) + (float64(size)-7*pixelSize)/2
+    for row, line := range glyph {
+        for col, char := range line {
+            if char != '#' {
+                continue
+            }
+            x0 := int(glyphX + float64(col)*pixelSize + 0.5)
+            y0 := int(glyphY + float64(row)*pixelSize + 0.5)
+            x1 := int(glyphX + float64(col+1)*pixelSize + 0.5)
+            y1 := int(glyphY + float64(row+1)*pixelSize + 0.5)
+            fillRect(img, x0, y0, max(x1, x0+1), max(y1, y0+1), fill)
+        }
+    }
+}
+
+// renderBoard draws the board of game, where digits that are not givens are drawn as entered by the user
+func renderBoard(game sudoku.Sudoku, givens [9][9]bool, options renderOptions) *image.RGBA {
+    cellSize := options.cellSize
+    margin := cellSize / 4
+    size := 9*cellSize + 2*margin
+    img := image.NewRGBA(image.Rect(0, 0, size, size))
+    fillRect(img, 0, 0, size, size, renderBackground)
+    cellOrigin := func(row int, col int) (int, int) {
+        return margin + col*cellSize, margin + row*cellSize
+    }
+
+    var removed [9][9][9]bool
+    var placed [9][9]uint8
+    if options.step != nil {
+        for _, cell := range getStepSourceCells(*options.step) {
+            x, y := cellOrigin(cell[0], cell[1])
+            fillRect(img, x, y, x+cellSize, y+cellSize, renderSourceHighlight)
+        }
+        for i, cell := range options.step.TargetCells {
+            x, y := cellOrigin(cell[0], cell[1])
+            fillRect(img, x, y, x+cellSize, y+cellSize, renderTargetHighlight)
+            value := options.step.TargetValues[i]
+            if options.step.EffectType == sudoku.PlaceNumber {
+                placed[cell[0]][cell[1]] = value
+            } else {
+                removed[cell[0]][cell[1]][value-1] = true
+            }
+        }
+    }
+
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            x, y := cellOrigin(i, j)
+            if digit := game.Board[i][j]; digit != 0 {
+                fill := renderEntered
+                if givens[i][j] {
+                    fill = renderGiven
+                }
+                drawDigit(img, digit, x, y, cellSize, fill)
+            } else if placed[i][j] != 0 {
+                drawDigit(img, placed[i][j], x, y, cellSize, renderPlaced)
+            } else if options.candidates || options.step != nil {
+                subSize := cellSize / 3
+                for _, candidate := range sudoku.CellCandidates(&game, i, j) {
+                    fill := renderCandidate
+                    if removed[i][j][candidate-1] {
+                        fill = renderRemoved
+                    }
+                    subX := x + int(candidate-1)%3*subSize
+                    subY := y + int(candidate-1)/3*subSize
+                    drawDigit(img, candidate, subX, subY, subSize, fill)
+                }
+            }
+        }
+    }
+
+    for i := 0; i <= 9; i++ {
+        width := max(1, cellSize/30)
+        if i%3 == 0 {
+            width = max(2, cellSize/12)
+        }
+        offset := margin + i*cellSize - width/2
+        fillRect(img, margin-width/2, offset, size-margin+(width+1)/2, offset+width, renderLines)
+        fillRect(img, offset, margin-width/2, offset+width, size-margin+(width+1)/2, renderLines)
+    }
+    return img
+}
+
+// runRender renders the board of game with all digits as givens, optionally highlighting the next hint
+func runRender(path string, game sudoku.Sudoku, givens [9][9]bool, candidates bool, hint bool, cellSize int) error {
+    options := renderOptions{
+        cellSize:   cellSize,
+        candidates: candidates,
+    }
+    if hint {
+        if steps := sudoku.Hints(&game); len(steps) > 0 {
+            options.step = &steps[0]
+        }
+    }
+    return writePng(path, renderBoard(game, givens, options))
+}
+
+// getGivens treats all digits of board as givens
+func getGivens(board [9][9]uint8) [9][9]bool {
+    var givens [9][9]bool
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            givens[i][j] = board[i][j] != 0
+        }
+    }
+    return givens
+}
+
+func writePng(path string, img image.Image) error {
+    file, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if err := png.Encode(file, img); err != nil {
+        file.Close()
+        return err
+    }
+    return file.Close()
+}
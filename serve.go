@@ -0,0 +1,84 @@
+package main
+
+import (
+    "context"
+    _ "embed"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "os"
+    "os/signal"
+    "strconv"
+    "sync/atomic"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+//go:embed web/index.html
+var serveIndex []byte
+
+// newServeHandler serves the page to play in the browser and the puzzles it asks for, which are
+// generated with options, the difficulty parameter of a request overrides the difficulty of options,
+// with a seed other than -1 the n-th puzzle is generated with seed + n like in GenerateMany
+func newServeHandler(options sudoku.GeneratorOptions, seed int, cores int) http.Handler {
+    var numPuzzles atomic.Int64
+    mux := http.NewServeMux()
+    mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html; charset=utf-8")
+        w.Write(serveIndex)
+    })
+    mux.HandleFunc("GET /puzzle", func(w http.ResponseWriter, r *http.Request) {
+        puzzleOptions := options
+        if value := r.URL.Query().Get("difficulty"); value != "" {
+            difficulty, err := strconv.Atoi(value)
+            if err != nil {
+                http.Error(w, fmt.Sprintf("invalid difficulty %q", value), http.StatusBadRequest)
+                return
+            }
+            puzzleOptions.Difficulty = difficulty
+        }
+        puzzleSeed := seed
+        if seed != -1 {
+            puzzleSeed += int(numPuzzles.Add(1) - 1)
+        }
+        // the generator stops if the browser gives up on the request
+        game, err := sudoku.Generate(r.Context(), puzzleOptions, puzzleSeed, cores)
+        var optionsError *sudoku.OptionsError
+        if errors.As(err, &optionsError) {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        } else if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        var jsonSeed *int
+        if seed != -1 {
+            jsonSeed = &puzzleSeed
+        }
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(getJsonSudoku(game, jsonSeed))
+    })
+    return mux
+}
+
+// runServe serves the page to play generated sudokus in the browser on addr until it is interrupted
+func runServe(addr string, options sudoku.GeneratorOptions, seed int, cores int) error {
+    listener, err := net.Listen("tcp", addr)
+    if err != nil {
+        return err
+    }
+    server := &http.Server{Handler: newServeHandler(options, seed, cores)}
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+    defer stop()
+    go func() {
+        <-ctx.Done()
+        server.Shutdown(context.Background())
+    }()
+    fmt.Fprintf(os.Stderr, "Serving sudokus on http://%s, press Ctrl+C to stop\n", listener.Addr())
+    if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+func TestServeHandler(t *testing.T) {
+    handler := newServeHandler(sudoku.GeneratorOptions{Difficulty: 1}, 1, 1)
+    tests := []struct {
+        name        string
+        target      string
+        status      int
+        contentType string
+    }{
+        {"page", "/", http.StatusOK, "text/html; charset=utf-8"},
+        {"puzzle", "/puzzle", http.StatusOK, "application/json"},
+        {"puzzle of a difficulty", "/puzzle?difficulty=2", http.StatusOK, "application/json"},
+        {"invalid difficulty", "/puzzle?difficulty=easy", http.StatusBadRequest, ""},
+        {"unknown difficulty", "/puzzle?difficulty=9", http.StatusBadRequest, ""},
+        {"unknown path", "/solve", http.StatusNotFound, ""},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            recorder := httptest.NewRecorder()
+            handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.target, nil))
+            if recorder.Code != test.status {
+                t.Fatalf("status is %d, want %d: %s", recorder.Code, test.status, recorder.Body)
+            }
+            if test.contentType != "" && recorder.Header().Get("Content-Type") != test.contentType {
+                t.Errorf("content type is %q, want %q", recorder.Header().Get("Content-Type"), test.contentType)
+            }
+            if test.contentType != "application/json" {
+                return
+            }
+            var game jsonSudoku
+            if err := json.NewDecoder(recorder.Body).Decode(&game); err != nil {
+                t.Fatalf("could not decode puzzle: %v", err)
+            }
+            board, err := sudoku.ParseBoard(game.Puzzle)
+            if err != nil {
+                t.Fatalf("could not parse puzzle: %v", err)
+            } else if _, err := sudoku.New(board); err != nil {
+                t.Errorf("puzzle %s is invalid: %v", game.Puzzle, err)
+            }
+        })
+    }
+}
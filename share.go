@@ -0,0 +1,152 @@
+package main
+
+import (
+    "encoding/base64"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// Share codes pack a game into a short URL-safe string. After a version byte, each cell is
+// stored in row major order as 2 bits for its type, followed by 4 bits for the digit of givens
+// and entered digits, or by 1 bit for whether an empty cell has pencil marks and 9 more bits
+// for the pencil marks if it has any. The bits are encoded with unpadded URL-safe base64.
+const shareCodeVersion = 1
+
+const (
+    emptyCellType uint8 = iota
+    givenCellType
+    enteredCellType
+)
+
+type bitWriter struct {
+    bytes []byte
+    bits  int
+}
+
+func (writer *bitWriter) write(value uint16, numBits int) {
+    for i := numBits - 1; i >= 0; i-- {
+        if writer.bits%8 == 0 {
+            writer.bytes = append(writer.bytes, 0)
+        }
+        if value&(1<<i) != 0 {
+            writer.bytes[writer.bits/8] |= 1 << (7 - writer.bits%8)
+        }
+        writer.bits++
+    }
+}
+
+type bitReader struct {
+    bytes []byte
+    bits  int
+}
+
+func (reader *bitReader) read(numBits int) (uint16, error) {
+    var value uint16
+    for i := 0; i < numBits; i++ {
+        if reader.bits/8 >= len(reader.bytes) {
+            return value, newPuzzleError("share code is too short")
+        }
+        value <<= 1
+        if reader.bytes[reader.bits/8]&(1<<(7-reader.bits%8)) != 0 {
+            value |= 1
+        }
+        reader.bits++
+    }
+    return value, nil
+}
+
+// encodeShareCode packs the board of game, whose digits are either givens or entered by the user,
+// and the pencil marks of its empty cells
+func encodeShareCode(game sudoku.Sudoku, givens [9][9]bool) string {
+    writer := new(bitWriter)
+    writer.write(shareCodeVersion, 8)
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            digit := game.Board[i][j]
+            if digit != 0 {
+                cellType := enteredCellType
+                if givens[i][j] {
+                    cellType = givenCellType
+                }
+                writer.write(uint16(cellType), 2)
+                writer.write(uint16(digit), 4)
+                continue
+            }
+            writer.write(uint16(emptyCellType), 2)
+            var marks uint16
+            for k := 0; k < 9; k++ {
+                if game.Candidates[i][j][k] {
+                    marks |= 1 << k
+                }
+            }
+            if marks == 0 {
+                writer.write(0, 1)
+            } else {
+                writer.write(1, 1)
+                writer.write(marks, 9)
+            }
+        }
+    }
+    return base64.RawURLEncoding.EncodeToString(writer.bytes)
+}
+
+// decodeShareCode unpacks a share code into the puzzle given by its givens, and the state of the game,
+// whose board also contains the entered digits and whose candidates are the pencil marks
+func decodeShareCode(code string) (sudoku.Sudoku, sudoku.Sudoku, error) {
+    var state sudoku.Sudoku
+    data, err := base64.RawURLEncoding.DecodeString(code)
+    if err != nil {
+        return sudoku.Sudoku{}, state, newPuzzleError("invalid share code: %v", err)
+    }
+    reader := &bitReader{bytes: data}
+    if version, err := reader.read(8); err != nil {
+        return sudoku.Sudoku{}, state, err
+    } else if version != shareCodeVersion {
+        return sudoku.Sudoku{}, state, newPuzzleError("unsupported share code version %d", version)
+    }
+    var givensBoard [9][9]uint8
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            cellType, err := reader.read(2)
+            if err != nil {
+                return sudoku.Sudoku{}, state, err
+            }
+            switch uint8(cellType) {
+            case givenCellType, enteredCellType:
+                digit, err := reader.read(4)
+                if err != nil {
+                    return sudoku.Sudoku{}, state, err
+                } else if digit < 1 || digit > 9 {
+                    return sudoku.Sudoku{}, state, newPuzzleError("invalid digit %d in r%dc%d", digit, i+1, j+1)
+                }
+                state.Board[i][j] = uint8(digit)
+                if uint8(cellType) == givenCellType {
+                    givensBoard[i][j] = uint8(digit)
+                }
+            case emptyCellType:
+                hasMarks, err := reader.read(1)
+                if err != nil || hasMarks == 0 {
+                    if err != nil {
+                        return sudoku.Sudoku{}, state, err
+                    }
+                    continue
+                }
+                marks, err := reader.read(9)
+                if err != nil {
+                    return sudoku.Sudoku{}, state, err
+                }
+                for k := 0; k < 9; k++ {
+                    state.Candidates[i][j][k] = marks&(1<<k) != 0
+                }
+            default:
+                return sudoku.Sudoku{}, state, newPuzzleError("invalid cell type %d in r%dc%d", cellType, i+1, j+1)
+            }
+        }
+    }
+    puzzle, err := sudoku.New(givensBoard)
+    if err != nil {
+        return puzzle, state, err
+    }
+    state.Solution = puzzle.Solution
+    return puzzle, state, nil
+}
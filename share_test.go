@@ -0,0 +1,84 @@
+package main
+
+import (
+    "testing"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+func TestShareCodeRoundTrip(t *testing.T) {
+    puzzle, err := sudoku.ParseBoard("050008000002050006308072094980040010500006000103020800209030058000100602070260409")
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        name  string
+        setup func(game *sudoku.Sudoku)
+    }{
+        {"givens only", func(game *sudoku.Sudoku) {}},
+        {"entered digits", func(game *sudoku.Sudoku) {
+            game.Board[0][0] = game.Solution[0][0]
+            // a wrong digit that is not a given
+            game.Board[8][0] = game.Solution[8][0]%9 + 1
+        }},
+        {"pencil marks", func(game *sudoku.Sudoku) {
+            game.Candidates[0][0] = [9]bool{true, false, false, false, true, false, false, false, true}
+            game.Candidates[4][4] = [9]bool{false, true}
+        }},
+        {"entered digits and pencil marks", func(game *sudoku.Sudoku) {
+            game.Board[0][2] = 4
+            game.Candidates[0][0] = [9]bool{true, true, true, true, true, true, true, true, true}
+        }},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            game, err := sudoku.New(puzzle)
+            if err != nil {
+                t.Fatal(err)
+            }
+            game.Candidates = [9][9][9]bool{}
+            test.setup(&game)
+            givens := getGivens(puzzle)
+
+            decodedPuzzle, state, err := decodeShareCode(encodeShareCode(game, givens))
+            if err != nil {
+                t.Fatalf("could not decode share code: %v", err)
+            }
+            if decodedPuzzle.Board != sudoku.Board(puzzle) {
+                t.Errorf("puzzle is %s, want %s", decodedPuzzle.Board, sudoku.Board(puzzle))
+            }
+            if state.Board != game.Board {
+                t.Errorf("board is %s, want %s", state.Board, game.Board)
+            }
+            if state.Solution != game.Solution {
+                t.Errorf("solution is %s, want %s", state.Solution, game.Solution)
+            }
+            for i := 0; i < 9; i++ {
+                for j := 0; j < 9; j++ {
+                    if game.Board[i][j] == 0 && state.Candidates[i][j] != game.Candidates[i][j] {
+                        t.Errorf("pencil marks of r%dc%d are %v, want %v", i+1, j+1, state.Candidates[i][j], game.Candidates[i][j])
+                    }
+                }
+            }
+        })
+    }
+}
+
+func TestDecodeShareCodeRejectsInvalidCodes(t *testing.T) {
+    tests := []struct {
+        name string
+        code string
+    }{
+        {"empty", ""},
+        {"invalid base64", "not a share code!"},
+        {"truncated", encodeShareCode(sudoku.Empty(), [9][9]bool{})[:5]},
+        {"unsupported version", "_w"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if _, _, err := decodeShareCode(test.code); err == nil {
+                t.Errorf("decoding %q succeeded, want an error", test.code)
+            }
+        })
+    }
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// puzzle sheets are A4 pages, all coordinates are in points with the origin at the top left
+const (
+    pageWidth   = 595.0
+    pageHeight  = 842.0
+    pageMargin  = 40.0
+    labelSize   = 11.0
+    labelMargin = 8.0
+    // width of a digit in Helvetica relative to the font size
+    digitWidth = 0.556
+)
+
+// columns and rows of puzzles on a page for each supported number of puzzles per page
+var sheetLayouts = map[int][2]int{
+    1: {1, 1},
+    2: {1, 2},
+    4: {2, 2},
+    6: {2, 3},
+}
+
+type sheetCanvas interface {
+    newPage()
+    line(x1 float64, y1 float64, x2 float64, y2 float64, width float64)
+    // text draws with the left end of the baseline at x, y and a gray level between 0 (black) and 1 (white)
+    text(x float64, y float64, size float64, text string, gray float64)
+    finish() []byte
+}
+
+type sheetOptions struct {
+    perPage   int
+    solutions bool
+    labels    bool
+}
+
+// newSheetCanvas returns an SVG or PDF canvas depending on the extension of path
+func newSheetCanvas(path string, options sheetOptions) (sheetCanvas, error) {
+    if _, ok := sheetLayouts[options.perPage]; !ok {
+        return nil, newUsageError("unsupported number of puzzles per page %d, must be 1, 2, 4 or 6", options.perPage)
+    }
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".svg":
+        return &svgCanvas{}, nil
+    case ".pdf":
+        return &pdfCanvas{}, nil
+    }
+    return nil, newUsageError("unsupported file extension of %s, must be .svg or .pdf", path)
+}
+
+// writeSheets renders the puzzles to an SVG or PDF file depending on the extension of path,
+// followed by pages with their solutions if requested
+func writeSheets(path string, games []sudoku.Sudoku, options sheetOptions) error {
+    canvas, err := newSheetCanvas(path, options)
+    if err != nil {
+        return err
+    }
+    drawSheetPages(canvas, games, options, false)
+    if options.solutions {
+        drawSheetPages(canvas, games, options, true)
+    }
+    return os.WriteFile(path, canvas.finish(), 0o644)
+}
+
+func drawSheetPages(canvas sheetCanvas, games []sudoku.Sudoku, options sheetOptions, solutions bool) {
+    layout := sheetLayouts[options.perPage]
+    columns, rows := layout[0], layout[1]
+    slotWidth := (pageWidth - 2*pageMargin) / float64(columns)
+    slotHeight := (pageHeight - 2*pageMargin) / float64(rows)
+    gridSize := 0.9 * min(slotWidth, slotHeight-labelSize-labelMargin)
+    for i, game := range games {
+        slot := i % options.perPage
+        if slot == 0 {
+            canvas.newPage()
+        }
+        slotX := pageMargin + float64(slot%columns)*slotWidth
+        slotY := pageMargin + float64(slot/columns)*slotHeight
+        gridX := slotX + (slotWidth-gridSize)/2
+        gridY := slotY + (slotHeight-gridSize+labelSize+labelMargin)/2
+        if options.labels {
+            label := fmt.Sprintf("Puzzle %d - Difficulty %d", i+1, sudoku.Rate(&game))
+            if solutions {
+                label = fmt.Sprintf("Solution %d", i+1)
+            }
+            canvas.text(gridX, gridY-labelMargin, labelSize, label, 0)
+        }
+        drawSheetGrid(canvas, gridX, gridY, gridSize, game, solutions)
+    }
+}
+
+// drawSheetGrid draws the board of game, or its solution with the clues in black and the other digits in gray
+func drawSheetGrid(canvas sheetCanvas, x float64, y float64, size float64, game sudoku.Sudoku, solution bool) {
+    cellSize := size / 9
+    for i := 0; i <= 9; i++ {
+        width := 0.5
+        if i%3 == 0 {
+            width = 2
+        }
+        offset := float64(i) * cellSize
+        canvas.line(x, y+offset, x+size, y+offset, width)
+        canvas.line(x+offset, y, x+offset, y+size, width)
+    }
+    fontSize := 0.6 * cellSize
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            digit := game.Board[i][j]
+            gray := 0.0
+            if solution && digit == 0 {
+                digit = game.Solution[i][j]
+                gray = 0.5
+            }
+            if digit == 0 {
+                continue
+            }
+            textX := x + (float64(j)+0.5)*cellSize - digitWidth*fontSize/2
+            textY := y + (float64(i)+0.5)*cellSize + 0.35*fontSize
+            canvas.text(textX, textY, fontSize, fmt.Sprintf("%d", digit), gray)
+        }
+    }
+}
+
+// svgCanvas stacks all pages vertically in a single image
+type svgCanvas struct {
+    pages []*strings.Builder
+}
+
+const svgPageGap = 20.0
+
+func (canvas *svgCanvas) newPage() {
+    canvas.pages = append(canvas.pages, new(strings.Builder))
+}
+
+func (canvas *svgCanvas) offset() float64 {
+    return float64(len(canvas.pages)-1) * (pageHeight + svgPageGap)
+}
+
+func (canvas *svgCanvas) line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+    offset := canvas.offset()
+    fmt.Fprintf(canvas.pages[len(canvas.pages)-1],
+        "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"black\" stroke-width=\"%.2f\" stroke-linecap=\"square\"/>\n",
+        x1, y1+offset, x2, y2+offset, width)
+}
+
+func (canvas *svgCanvas) text(x float64, y float64, size float64, text string, gray float64) {
+    level := int(255 * gray)
+    fmt.Fprintf(canvas.pages[len(canvas.pages)-1],
+        "<text x=\"%.2f\" y=\"%.2f\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"%.2f\" fill=\"rgb(%d,%d,%d)\">%s</text>\n",
+        x, y+canvas.offset(), size, level, level, level, escapeXml(text))
+}
+
+func (canvas *svgCanvas) finish() []byte {
+    height := float64(len(canvas.pages))*(pageHeight+svgPageGap) - svgPageGap
+    builder := new(strings.Builder)
+    fmt.Fprintf(builder, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0fpt\" height=\"%.0fpt\" viewBox=\"0 0 %.0f %.0f\">\n",
+        pageWidth, height, pageWidth, height)
+    for i, page := range canvas.pages {
+        fmt.Fprintf(builder, "<rect x=\"0\" y=\"%.2f\" width=\"%.0f\" height=\"%.0f\" fill=\"white\" stroke=\"lightgray\"/>\n",
+            float64(i)*(pageHeight+svgPageGap), pageWidth, pageHeight)
+        builder.WriteString(page.String())
+    }
+    builder.WriteString("</svg>\n")
+    return []byte(builder.String())
+}
+
+func escapeXml(text string) string {
+    return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// pdfCanvas writes a minimal PDF using the standard Helvetica font, which every PDF reader provides
+type pdfCanvas struct {
+    pages []*strings.Builder
+}
+
+func (canvas *pdfCanvas) newPage() {
+    canvas.pages = append(canvas.pages, new(strings.Builder))
+}
+
+func (canvas *pdfCanvas) line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+    // PDF coordinates start at the bottom left
+    fmt.Fprintf(canvas.pages[len(canvas.pages)-1], "%.2f w 2 J %.2f %.2f m %.2f %.2f l S\n",
+        width, x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+func (canvas *pdfCanvas) text(x float64, y float64, size float64, text string, gray float64) {
+    escaped := strings.NewReplacer("\\", "\\\\", "(", "\\(", ")", "\\)").Replace(text)
+    fmt.Fprintf(canvas.pages[len(canvas.pages)-1], "%.2f g BT /F1 %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+        gray, size, x, pageHeight-y, escaped)
+}
+
+func (canvas *pdfCanvas) finish() []byte {
+    // objects: 1 catalog, 2 page tree, 3 font, then a page and its content stream for each page
+    var objects []string
+    var kids []string
+    for i := range canvas.pages {
+        kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+    }
+    objects = append(objects,
+        "<< /Type /Catalog /Pages 2 0 R >>",
+        fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(canvas.pages)),
+        "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+    for i, page := range canvas.pages {
+        content := page.String()
+        objects = append(objects,
+            fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+                pageWidth, pageHeight, 5+2*i),
+            fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+    }
+    buffer := new(bytes.Buffer)
+    buffer.WriteString("%PDF-1.4\n")
+    offsets := make([]int, len(objects))
+    for i, object := range objects {
+        offsets[i] = buffer.Len()
+        fmt.Fprintf(buffer, "%d 0 obj\n%s\nendobj\n", i+1, object)
+    }
+    xrefOffset := buffer.Len()
+    fmt.Fprintf(buffer, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+    for _, offset := range offsets {
+        fmt.Fprintf(buffer, "%010d 00000 n \n", offset)
+    }
+    fmt.Fprintf(buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+    return buffer.Bytes()
+}
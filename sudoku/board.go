@@ -0,0 +1,39 @@
+package sudoku
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Board is a grid of digits row by row, 0 for empty cells
+type Board [9][9]uint8
+
+// String returns the common 81 character line format of the board,
+// listing the cells row by row with 0 for empty cells
+func (board Board) String() string {
+    builder := new(strings.Builder)
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            builder.WriteByte('0' + board[i][j])
+        }
+    }
+    return builder.String()
+}
+
+// ParseBoard parses the 81 character line format, empty cells can be given as 0 or '.'
+func ParseBoard(line string) (Board, error) {
+    var board Board
+    line = strings.TrimSpace(line)
+    if len(line) != 81 {
+        return board, &PuzzleError{fmt.Sprintf("expected 81 characters, got %d", len(line))}
+    }
+    for idx, char := range line {
+        if char == '.' {
+            continue
+        } else if char < '0' || char > '9' {
+            return board, &PuzzleError{fmt.Sprintf("invalid character %q at position %d", char, idx+1)}
+        }
+        board[idx/9][idx%9] = uint8(char - '0')
+    }
+    return board, nil
+}
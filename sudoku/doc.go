@@ -0,0 +1,20 @@
+// Package sudoku generates, solves and rates sudokus.
+//
+// A Board is parsed from or formatted as the common 81 character line format with 0 for empty
+// cells. New turns a board with exactly one solution into a Sudoku, which holds the solution and
+// the candidates of the empty cells, and Generate creates new ones of a given difficulty until it
+// finds one or ctx is done:
+//
+//	game, err := sudoku.Generate(ctx, sudoku.GeneratorOptions{Method: sudoku.FillMethod, Difficulty: 3}, -1, -1)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(game.Board, game.Solution)
+//
+// Sudokus are rated by solving them with human strategies of increasing difficulty: Hints returns
+// the steps of the easiest strategy that makes progress, SolvePath the steps of a complete solve,
+// and Rate the difficulty of the hardest strategy needed, from 1 to MaxDifficulty.
+//
+// Every Strategy has a name and a difficulty and finds the steps it allows in a sudoku. Register adds
+// custom strategies, which are then used by Hints, SolvePath, Rate and Generate like the built-in ones.
+package sudoku
@@ -0,0 +1,53 @@
+package sudoku
+
+import "fmt"
+
+// PuzzleError reports a board that cannot be used as a puzzle
+type PuzzleError struct {
+    reason string
+}
+
+func (err *PuzzleError) Error() string {
+    return err.reason
+}
+
+// errors of New and Solve for boards that are not proper puzzles
+var (
+    ErrDuplicateDigits   = &PuzzleError{"board contains duplicate digits"}
+    ErrNoSolution        = &PuzzleError{"board has no solution"}
+    ErrMultipleSolutions = &PuzzleError{"board has more than one solution"}
+)
+
+// InternalError reports an invalid state of the generator or solver, which is a bug
+type InternalError struct {
+    reason string
+}
+
+func (err *InternalError) Error() string {
+    return err.reason
+}
+
+// errors of Generate if it reaches an invalid state
+var (
+    ErrInvalidSudoku   = &InternalError{"generated an invalid sudoku"}
+    ErrInvalidSolution = &InternalError{"generated an invalid solution"}
+)
+
+// OptionsError reports GeneratorOptions or a number of workers that Generate cannot use
+type OptionsError struct {
+    reason string
+}
+
+func (err *OptionsError) Error() string {
+    return err.reason
+}
+
+func newOptionsError(format string, args ...any) error {
+    return &OptionsError{fmt.Sprintf(format, args...)}
+}
+
+// errors of Generate if the mask of the options cannot give a sudoku that meets them
+var (
+    ErrMaskNotUnique  = &OptionsError{"found no digits for the clues of the mask that give a unique solution"}
+    ErrMaskDifficulty = &OptionsError{"found no digits for the clues of the mask that give the requested difficulty and strategy"}
+)
@@ -0,0 +1,920 @@
+package sudoku
+
+import (
+    "context"
+    "fmt"
+    "math/rand/v2"
+    "runtime"
+    "slices"
+    "sync"
+)
+
+// MinClueCount is the smallest number of clues, no sudoku with fewer clues has a unique solution
+const MinClueCount = 17
+
+// MaxMinimalClueCount is the largest number of clues the generator accepts as a minimum for minimal
+// sudokus, minimal sudokus with more clues exist but are too rare to be found by chance
+const MaxMinimalClueCount = 35
+
+const (
+    // fill random cells of an empty grid until the solution is unique
+    FillMethod = "fill"
+    // remove clues from a random solved grid while the solution stays unique
+    DigMethod = "dig"
+)
+
+// GenerationMethods are the methods Generate supports
+var GenerationMethods = []string{FillMethod, DigMethod}
+
+// GeneratorOptions select the method of Generate and the constraints the generated sudoku has to meet
+type GeneratorOptions struct {
+    Method string // empty for FillMethod
+    // one of ValidDifficulties, 0 picks a random difficulty, or that of RequiredStrategy if it is set,
+    // with a Mask and without a RequiredStrategy 0 accepts whatever difficulty the mask gives
+    Difficulty int
+    // only used by the dig method, remove clues in pairs that are symmetric under 180 degree rotation
+    Symmetric bool
+    Minimal   bool
+    MinClues  int // 0 for no minimum
+    MaxClues  int // 0 for no maximum
+    // name of a strategy the solve path has to use, empty for any strategy
+    RequiredStrategy string
+    // whether the required strategy also has to be the hardest one used
+    RequireHardest bool
+    // cells that have to be clues, nil to let the method choose them
+    Mask *[9][9]bool
+}
+
+// Sudoku is a puzzle with its unique solution and the candidates of its empty cells,
+// Board holds the clues and the digits placed so far
+type Sudoku struct {
+    Board           Board
+    Solution        Board
+    Candidates      [9][9][9]bool
+    CandidatesCount [9][9]int
+}
+
+// Empty returns a sudoku without clues where every cell has all candidates
+func Empty() Sudoku {
+    var game Sudoku
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            game.CandidatesCount[i][j] = 9
+            for k := 0; k < 9; k++ {
+                game.Candidates[i][j][k] = true
+            }
+        }
+    }
+    return game
+}
+
+// New creates a game from a given board, which has to have exactly one solution
+func New(board Board) (Sudoku, error) {
+    game := Empty()
+    if !IsValidBoard(board) {
+        return game, ErrDuplicateDigits
+    }
+    game.Board = board
+    ComputeCandidates(&game)
+    numSolutions, solution := CountSolutions(game, 2)
+    if numSolutions == 0 {
+        return game, ErrNoSolution
+    } else if numSolutions > 1 {
+        return game, ErrMultipleSolutions
+    }
+    game.Solution = solution
+    return game, nil
+}
+
+// Solve returns the solution of board, which has to be unique
+func Solve(board Board) (Board, error) {
+    game, err := New(board)
+    return game.Solution, err
+}
+
+func isValidSet(set []uint8) bool {
+    seen := make(map[uint8]bool)
+    for _, value := range set {
+        if value != 0 {
+            if seen[value] {
+                return false
+            }
+            seen[value] = true
+        }
+    }
+    return true
+}
+
+// IsValidBoard returns whether no digit appears more than once in a row, column or box
+func IsValidBoard(board Board) bool {
+    for i := 0; i < 9; i++ {
+        row := board[i][:]
+
+        boxRowStart, boxColumnStart := BoxStarts(i)
+
+        column := make([]uint8, 9)
+        box := make([]uint8, 9)
+
+        for j := 0; j < 9; j++ {
+            column[j] = board[j][i]
+            box[j] = board[boxRowStart+j/3][boxColumnStart+j%3]
+        }
+
+        isValidRow := isValidSet(row)
+        isValidColumn := isValidSet(column)
+        isValidBox := isValidSet(box)
+
+        if !isValidRow || !isValidColumn || !isValidBox {
+            return false
+        }
+    }
+    return true
+}
+
+// IsValidSolvedBoard returns whether board is completely and correctly filled
+func IsValidSolvedBoard(board Board) bool {
+    return (IsValidBoard(board) && IsSolved(board))
+}
+
+// IsValidUnsolvedBoard returns whether board has empty cells and no duplicate digits
+func IsValidUnsolvedBoard(board Board) bool {
+    return (IsValidBoard(board) && !IsSolved(board))
+}
+
+func fillRandomCell(game *Sudoku, rng *rand.Rand) {
+    row, col := getRandomEmptyCell(game.Board, rng)
+    candidates := CellCandidates(game, row, col)
+    insertedValue, err := selectRandomCandidate(candidates, rng)
+    if err != nil {
+        fillRandomCell(game, rng)
+    }
+    game.Board[row][col] = insertedValue
+    updateCandidates(row, col, insertedValue, game)
+}
+
+// generatorResult is sent by the first worker that finds a puzzle or runs into an error
+type generatorResult struct {
+    game Sudoku
+    err  error
+}
+
+// Validate returns an OptionsError if Generate cannot meet options
+func (options GeneratorOptions) Validate() error {
+    if options.Method != "" && !slices.Contains(GenerationMethods, options.Method) {
+        return newOptionsError("method must be one of %q", GenerationMethods)
+    } else if !slices.Contains(ValidDifficulties, options.Difficulty) {
+        return newOptionsError("difficulty must be between 0 and %d", MaxDifficulty)
+    } else if options.Symmetric && options.Method != DigMethod {
+        return newOptionsError("symmetric clues need the dig method")
+    }
+
+    if options.RequiredStrategy != "" {
+        strategy, ok := LookupStrategy(options.RequiredStrategy)
+        if !ok {
+            return newOptionsError("unknown strategy %q", options.RequiredStrategy)
+        } else if options.Difficulty != 0 && options.Difficulty < strategy.Difficulty() {
+            return newOptionsError("%s requires a difficulty of at least %d", strategy.Name(), strategy.Difficulty())
+        } else if options.RequireHardest && options.Difficulty != 0 && options.Difficulty != strategy.Difficulty() {
+            return newOptionsError("%s can only be the hardest strategy of a sudoku of difficulty %d", strategy.Name(), strategy.Difficulty())
+        }
+    } else if options.RequireHardest {
+        return newOptionsError("requiring the hardest strategy needs a required strategy")
+    }
+
+    if options.MinClues < 0 || options.MaxClues < 0 {
+        return newOptionsError("clue counts must not be negative")
+    } else if options.MaxClues > 0 && options.MaxClues < MinClueCount {
+        return newOptionsError("max clues must be at least %d", MinClueCount)
+    } else if options.MaxClues > 0 && options.MinClues > options.MaxClues {
+        return newOptionsError("min clues must not be larger than max clues")
+    } else if options.MinClues > 80 {
+        return newOptionsError("min clues must be at most 80, a sudoku needs an empty cell")
+    } else if options.Minimal && options.MinClues > MaxMinimalClueCount {
+        return newOptionsError("min clues must be at most %d for minimal sudokus", MaxMinimalClueCount)
+    }
+
+    if options.Mask != nil {
+        if numClues := countMaskClues(*options.Mask); numClues < MinClueCount {
+            return newOptionsError("mask has %d clues, but needs at least %d", numClues, MinClueCount)
+        } else if numClues > 80 {
+            return newOptionsError("mask has %d clues, but must have at most 80, a sudoku needs an empty cell", numClues)
+        } else if options.Method == DigMethod || options.Minimal || options.MinClues > 0 || options.MaxClues > 0 {
+            return newOptionsError("a mask cannot be combined with the dig method, minimal sudokus or clue counts")
+        }
+    }
+    return nil
+}
+
+func countMaskClues(mask [9][9]bool) int {
+    numClues := 0
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if mask[i][j] {
+                numClues++
+            }
+        }
+    }
+    return numClues
+}
+
+// Generate creates a sudoku meeting options on numWorkers goroutines, -1 for one per core,
+// with a seed other than -1 the same sudoku is generated every time, it returns an OptionsError
+// for invalid options and the error of ctx if ctx is done before a sudoku is found, and only
+// after all of its workers stopped
+func Generate(ctx context.Context, options GeneratorOptions, seed int, numWorkers int) (Sudoku, error) {
+    if err := options.Validate(); err != nil {
+        return Sudoku{}, err
+    } else if numWorkers != -1 && numWorkers < 1 {
+        return Sudoku{}, newOptionsError("number of workers must be -1 or at least 1, got %d", numWorkers)
+    }
+    if seed == -1 {
+        seed = rand.Int()
+    } else {
+        // avoid seed 0
+        seed++
+    }
+    options = resolveOptions(options, seed)
+    ctx, cancel := context.WithCancel(ctx)
+    // cancelling stops all remaining workers at once
+    defer cancel()
+    result := make(chan generatorResult)
+    if numWorkers == -1 {
+        numWorkers = runtime.NumCPU()
+    }
+    worker := generateSudoku
+    if options.Mask != nil {
+        worker = maskSudoku
+    } else if options.Method == DigMethod {
+        worker = digSudoku
+    }
+    var wg sync.WaitGroup
+    for i := 1; i <= numWorkers; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            worker(ctx, options, seed*i, result)
+        }()
+    }
+    var first generatorResult
+    select {
+    case first = <-result:
+    case <-ctx.Done():
+        first.err = ctx.Err()
+    }
+    // no worker may outlive Generate, e.g. to keep using cores after a benchmark run
+    cancel()
+    wg.Wait()
+    return first.game, first.err
+}
+
+// resolveOptions fills in the defaults of valid options, the random difficulty for difficulty 0
+// is picked with seed, so that a fixed seed always picks the same one, with a mask difficulty 0
+// is kept to accept any difficulty, since most masks only give a few of them
+func resolveOptions(options GeneratorOptions, seed int) GeneratorOptions {
+    if options.Method == "" {
+        options.Method = FillMethod
+    }
+    if options.Difficulty == 0 && options.RequiredStrategy != "" {
+        options.Difficulty = StrategyDifficulty(options.RequiredStrategy)
+    } else if options.Difficulty == 0 && options.Mask == nil {
+        rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+        options.Difficulty = ValidDifficulties[rng.IntN(len(ValidDifficulties)-1)+1]
+    }
+    return options
+}
+
+// GenerateMany generates count puzzles one after another, a fixed seed is incremented for each puzzle
+func GenerateMany(ctx context.Context, options GeneratorOptions, seed int, numWorkers int, count int) ([]Sudoku, error) {
+    var games []Sudoku
+    for i := 0; i < count; i++ {
+        currentSeed := seed
+        if seed != -1 {
+            currentSeed += i
+        }
+        game, err := Generate(ctx, options, currentSeed, numWorkers)
+        if err != nil {
+            return games, err
+        }
+        games = append(games, game)
+    }
+    return games, nil
+}
+
+// sendResult hands the result of a worker to Generate unless another worker was faster
+func sendResult(ctx context.Context, game Sudoku, err error, result chan generatorResult) {
+    select {
+    case <-ctx.Done():
+    case result <- generatorResult{game, err}:
+    }
+}
+
+func generateSudoku(ctx context.Context, options GeneratorOptions, seed int, result chan generatorResult) {
+    rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        default:
+        }
+        game, ok := fillUntilUnique(ctx, options, rng)
+        var err error
+        if ok {
+            ok, err = finalizeSudoku(&game, options, rng)
+        }
+        if err == nil && !ok {
+            continue
+        }
+        // for easy difficulties there can be a race condition,
+        // so sending the result has to check for cancellation again
+        sendResult(ctx, game, err, result)
+        return
+    }
+}
+
+func digSudoku(ctx context.Context, options GeneratorOptions, seed int, result chan generatorResult) {
+    rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        default:
+        }
+        game, ok := digUntilDifficulty(ctx, options, rng)
+        var err error
+        if ok {
+            ok, err = finalizeSudoku(&game, options, rng)
+        }
+        if err == nil && !ok {
+            continue
+        }
+        sendResult(ctx, game, err, result)
+        return
+    }
+}
+
+// digUntilDifficulty removes clues from a random solved grid in random order, a removal is undone
+// if the solution is not unique anymore or the puzzle gets harder than the requested difficulty
+func digUntilDifficulty(ctx context.Context, options GeneratorOptions, rng *rand.Rand) (Sudoku, bool) {
+    game := Empty()
+    _, solution := countSolutions(game, 1, rng)
+    game.Board = solution
+    game.Solution = solution
+    var removedValues []uint8
+    for _, cells := range getDigOrder(options.Symmetric, rng) {
+        select {
+        case <-ctx.Done():
+            return game, false
+        default:
+        }
+        removedValues = removedValues[:0]
+        for _, cell := range cells {
+            removedValues = append(removedValues, game.Board[cell[0]][cell[1]])
+            game.Board[cell[0]][cell[1]] = 0
+        }
+        ComputeCandidates(&game)
+        numSolutions, _ := CountSolutions(game, 2)
+        // nothing is harder than the maximum difficulty, so we can skip rating in that case
+        if numSolutions != 1 || (options.Difficulty < MaxDifficulty && Rate(&game) > options.Difficulty) {
+            for i, cell := range cells {
+                game.Board[cell[0]][cell[1]] = removedValues[i]
+            }
+        }
+    }
+    ComputeCandidates(&game)
+    return game, true
+}
+
+// limits of the searches of maskSudoku, variables so that tests can lower them
+var (
+    // maxMaskSearchNodes is the number of boards a single search of fillMask visits, including those
+    // visited to count the solutions of its assignments, before it gives up
+    maxMaskSearchNodes = 200000
+    // maxMaskSearches is the number of searches in a row without a unique solution after which
+    // maskSudoku gives up on the mask
+    maxMaskSearches = 20
+    // maxMaskAttempts is the number of searches after which maskSudoku gives up on finding a sudoku
+    // with the requested difficulty and strategy for the mask
+    maxMaskAttempts = 200
+)
+
+// maskSudoku searches for digits for the cells of the mask, such that the resulting puzzle
+// has a unique solution and the requested difficulty
+func maskSudoku(ctx context.Context, options GeneratorOptions, seed int, result chan generatorResult) {
+    rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+    failedSearches := 0
+    for attempt := 1; ; attempt++ {
+        select {
+        case <-ctx.Done():
+            return
+        default:
+        }
+        game, ok := fillMask(*options.Mask, rng)
+        var err error
+        if ok {
+            failedSearches = 0
+            ok, err = finalizeSudoku(&game, options, rng)
+        } else if failedSearches++; failedSearches >= maxMaskSearches {
+            err = ErrMaskNotUnique
+        }
+        if err == nil && !ok && attempt >= maxMaskAttempts {
+            err = ErrMaskDifficulty
+        }
+        if err == nil && !ok {
+            continue
+        }
+        sendResult(ctx, game, err, result)
+        return
+    }
+}
+
+// fillMask assigns digits to the cells of the mask in random order by backtracking, it returns false
+// if it did not find an assignment with a unique solution within maxMaskSearchNodes visited boards
+func fillMask(mask [9][9]bool, rng *rand.Rand) (Sudoku, bool) {
+    var cells [][2]int
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if mask[i][j] {
+                cells = append(cells, [2]int{i, j})
+            }
+        }
+    }
+    rng.Shuffle(len(cells), func(i, j int) {
+        cells[i], cells[j] = cells[j], cells[i]
+    })
+    budget := maxMaskSearchNodes
+    return assignMask(Empty(), cells, rng, &budget)
+}
+
+// assignMask tries the candidates of the first of cells and continues with the others, an assignment
+// without a solution is pruned, and as soon as the solution is unique the remaining cells are filled
+// from it, since further clues cannot make it ambiguous again
+func assignMask(game Sudoku, cells [][2]int, rng *rand.Rand, budget *int) (Sudoku, bool) {
+    if len(cells) == 0 {
+        return game, false
+    }
+    row, col := cells[0][0], cells[0][1]
+    candidates := CellCandidates(&game, row, col)
+    rng.Shuffle(len(candidates), func(i, j int) {
+        candidates[i], candidates[j] = candidates[j], candidates[i]
+    })
+    for _, candidate := range candidates {
+        nextGame := game
+        nextGame.Board[row][col] = candidate
+        updateCandidates(row, col, candidate, &nextGame)
+        var solution Board
+        // without a budget, proving that an assignment has no solution can take minutes
+        numSolutions := searchSolutions(nextGame, 2, nil, budget, func(found Board) {
+            solution = found
+        })
+        if *budget <= 0 {
+            return game, false
+        } else if numSolutions == 0 {
+            continue
+        } else if numSolutions == 1 {
+            for _, cell := range cells[1:] {
+                nextGame.Board[cell[0]][cell[1]] = solution[cell[0]][cell[1]]
+            }
+            ComputeCandidates(&nextGame)
+            nextGame.Solution = solution
+            return nextGame, true
+        }
+        if filledGame, ok := assignMask(nextGame, cells[1:], rng, budget); ok {
+            return filledGame, true
+        }
+    }
+    return game, false
+}
+
+// getDigOrder returns the groups of cells in the order in which they are removed by the dig method,
+// symmetric groups consist of a cell and its counterpart under 180 degree rotation
+func getDigOrder(symmetric bool, rng *rand.Rand) [][][2]int {
+    var groups [][][2]int
+    for cellIdx := 0; cellIdx < 81; cellIdx++ {
+        row, col := cellIdx/9, cellIdx%9
+        if !symmetric {
+            groups = append(groups, [][2]int{{row, col}})
+        } else if cellIdx < 40 {
+            groups = append(groups, [][2]int{{row, col}, {8 - row, 8 - col}})
+        } else if cellIdx == 40 {
+            groups = append(groups, [][2]int{{row, col}})
+        }
+    }
+    rng.Shuffle(len(groups), func(i, j int) {
+        groups[i], groups[j] = groups[j], groups[i]
+    })
+    return groups
+}
+
+// fillUntilUnique fills random cells of an empty grid until the puzzle has exactly one solution,
+// it returns false if it was stopped or the puzzle exceeded the maximum number of clues
+func fillUntilUnique(ctx context.Context, options GeneratorOptions, rng *rand.Rand) (Sudoku, bool) {
+    game := Empty()
+    var currentSolution Board
+    var numSolutions int
+    previousNumSolutions := 2
+    var previousGame Sudoku
+    // fill in 5 random cells according to the sudoku rules without checking for number of solutions
+    // I'm pretty sure there cannot be a board with <5 filled cells that has 0 solutions
+    for i := 0; i < 5; i++ {
+        previousGame = game
+        fillRandomCell(&game, rng)
+    }
+    // now start checking for number of solutions
+    isRetry := false
+    for {
+        select {
+        case <-ctx.Done():
+            return game, false
+        default:
+            if isRetry {
+                numSolutions = previousNumSolutions
+            } else {
+                numSolutions, currentSolution = CountSolutions(game, 2)
+            }
+            if numSolutions == 1 {
+                game.Solution = currentSolution
+                return game, true
+            } else if numSolutions == 0 {
+                isRetry = true
+                game = previousGame
+            } else {
+                // a minimal puzzle may still end up below the maximum
+                if !options.Minimal && options.MaxClues > 0 && CountClues(game.Board) >= options.MaxClues {
+                    return game, false
+                }
+                previousGame = game
+                previousNumSolutions = numSolutions
+                fillRandomCell(&game, rng)
+                isRetry = false
+            }
+        }
+    }
+}
+
+// finalizeSudoku validates a uniquely solvable game, applies the post-processing requested
+// in options and reports whether the result satisfies them
+func finalizeSudoku(game *Sudoku, options GeneratorOptions, rng *rand.Rand) (bool, error) {
+    if !IsValidUnsolvedBoard(game.Board) {
+        return false, ErrInvalidSudoku
+    }
+    if !IsValidSolvedBoard(game.Solution) {
+        return false, ErrInvalidSolution
+    }
+    if options.Minimal {
+        makeMinimal(game, options.Symmetric, rng)
+    } else {
+        addClues(game, options.MinClues, rng)
+    }
+    numClues := CountClues(game.Board)
+    if numClues < options.MinClues {
+        return false, nil
+    } else if options.MaxClues > 0 && numClues > options.MaxClues {
+        return false, nil
+    }
+    path, solved := SolvePath(game)
+    difficulty := MaxDifficulty
+    if solved {
+        difficulty = PathDifficulty(path)
+    }
+    if options.Difficulty != 0 && difficulty != options.Difficulty {
+        return false, nil
+    }
+    if options.RequiredStrategy == "" {
+        return true, nil
+    } else if !pathUsesStrategy(path, options.RequiredStrategy) {
+        return false, nil
+    }
+    return !options.RequireHardest || StrategyDifficulty(options.RequiredStrategy) == difficulty, nil
+}
+
+// makeMinimal removes clues in random order as long as the puzzle keeps a unique solution,
+// afterwards every remaining clue is necessary, symmetric puzzles lose their clues in the pairs
+// of the dig method, so they stay symmetric and every remaining pair is necessary
+func makeMinimal(game *Sudoku, symmetric bool, rng *rand.Rand) {
+    var removedValues []uint8
+    for _, cells := range getDigOrder(symmetric, rng) {
+        removedValues = removedValues[:0]
+        for _, cell := range cells {
+            removedValues = append(removedValues, game.Board[cell[0]][cell[1]])
+            game.Board[cell[0]][cell[1]] = 0
+        }
+        ComputeCandidates(game)
+        if numSolutions, _ := CountSolutions(*game, 2); numSolutions != 1 {
+            for i, cell := range cells {
+                game.Board[cell[0]][cell[1]] = removedValues[i]
+            }
+        }
+    }
+    ComputeCandidates(game)
+}
+
+// addClues fills random empty cells with their solution until game has at least minClues clues,
+// which keeps the solution unique, so a minimum the method did not reach on its own can still be met
+func addClues(game *Sudoku, minClues int, rng *rand.Rand) {
+    numClues := CountClues(game.Board)
+    if numClues >= minClues {
+        // leave the random numbers to the generator, so a fixed seed keeps its sudoku
+        return
+    }
+    for _, cellIdx := range rng.Perm(81) {
+        if numClues >= minClues {
+            break
+        }
+        row, col := cellIdx/9, cellIdx%9
+        if game.Board[row][col] == 0 {
+            game.Board[row][col] = game.Solution[row][col]
+            numClues++
+        }
+    }
+    ComputeCandidates(game)
+}
+
+// IsMinimal returns whether removing any clue of game would allow more than one solution
+func IsMinimal(game Sudoku) bool {
+    for row := 0; row < 9; row++ {
+        for col := 0; col < 9; col++ {
+            if game.Board[row][col] == 0 {
+                continue
+            }
+            reducedGame := game
+            reducedGame.Board[row][col] = 0
+            ComputeCandidates(&reducedGame)
+            if numSolutions, _ := CountSolutions(reducedGame, 2); numSolutions == 1 {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+// CountClues returns the number of filled cells of board
+func CountClues(board Board) int {
+    numClues := 0
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if board[i][j] != 0 {
+                numClues++
+            }
+        }
+    }
+    return numClues
+}
+
+// CountSolutions counts the solutions of game, stopping as soon as limit is reached,
+// and returns the last solution it found
+func CountSolutions(game Sudoku, limit int) (int, Board) {
+    return countSolutions(game, limit, nil)
+}
+
+// countSolutions works like CountSolutions, but with an rng it tries the candidates in random order,
+// so that the first solution of an empty grid is a random solved grid
+func countSolutions(game Sudoku, limit int, rng *rand.Rand) (int, Board) {
+    var solution Board
+    numSolutions := searchSolutions(game, limit, rng, nil, func(found Board) {
+        solution = found
+    })
+    return numSolutions, solution
+}
+
+// FindSolutions returns up to limit solutions of game
+func FindSolutions(game Sudoku, limit int) []Board {
+    var solutions []Board
+    searchSolutions(game, limit, nil, nil, func(found Board) {
+        solutions = append(solutions, found)
+    })
+    return solutions
+}
+
+// searchSolutions is the backtracking search behind CountSolutions and FindSolutions, it calls found
+// with every solution until limit is reached and returns the number of solutions found, with an rng
+// the branches are tried in random order, and with a budget every visited board uses up one node of it
+// and the search stops when it is used up
+func searchSolutions(game Sudoku, limit int, rng *rand.Rand, budget *int, found func(solution Board)) int {
+    if budget != nil {
+        if *budget <= 0 {
+            return 0
+        }
+        *budget--
+    }
+    if IsSolved(game.Board) {
+        found(game.Board)
+        return 1
+    }
+    branches := getSearchBranches(&game)
+    if rng != nil {
+        rng.Shuffle(len(branches), func(i, j int) {
+            branches[i], branches[j] = branches[j], branches[i]
+        })
+    }
+    numSolutions := 0
+    for _, branch := range branches {
+        row, col, digit := branch.row, branch.col, branch.digit
+        nextGame := game
+        nextGame.Board[row][col] = digit
+        updateCandidates(row, col, digit, &nextGame)
+        numSolutions += searchSolutions(nextGame, limit-numSolutions, rng, budget, found)
+        if numSolutions >= limit {
+            break
+        }
+    }
+    return numSolutions
+}
+
+// searchBranch is a digit searchSolutions tries in a cell
+type searchBranch struct {
+    row   int
+    col   int
+    digit uint8
+}
+
+// getSearchBranches returns the candidates of the most constrained cell, or the cells left for the digit
+// with the fewest cells left in a row, column or box if there are fewer of them, exactly one of the
+// branches holds in every solution, no branches are left if a cell or a digit has no options at all,
+// which prunes boards without solutions long before their cells run out of candidates
+func getSearchBranches(game *Sudoku) []searchBranch {
+    row, col := getMostConstrainedCell(game)
+    var branches []searchBranch
+    for _, candidate := range CellCandidates(game, row, col) {
+        branches = append(branches, searchBranch{row, col, candidate})
+    }
+    for _, context := range []Context{Row, Column, Box} {
+        for contextIdx := 0; contextIdx < 9; contextIdx++ {
+            var isPlaced [9]bool
+            var numCells [9]int
+            for cellIdx := 0; cellIdx < 9; cellIdx++ {
+                cellRow, cellCol := ContextCell(context, contextIdx, cellIdx)
+                if digit := game.Board[cellRow][cellCol]; digit != 0 {
+                    isPlaced[digit-1] = true
+                    continue
+                }
+                for k := 0; k < 9; k++ {
+                    if game.Candidates[cellRow][cellCol][k] {
+                        numCells[k]++
+                    }
+                }
+            }
+            for k := 0; k < 9; k++ {
+                if isPlaced[k] || numCells[k] >= len(branches) {
+                    continue
+                }
+                branches = branches[:0]
+                for cellIdx := 0; cellIdx < 9; cellIdx++ {
+                    cellRow, cellCol := ContextCell(context, contextIdx, cellIdx)
+                    if game.Board[cellRow][cellCol] == 0 && game.Candidates[cellRow][cellCol][k] {
+                        branches = append(branches, searchBranch{cellRow, cellCol, uint8(k + 1)})
+                    }
+                }
+                if len(branches) == 0 {
+                    return nil
+                }
+            }
+        }
+    }
+    return branches
+}
+
+// IsSolved returns whether board has no empty cells
+func IsSolved(board Board) bool {
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if board[i][j] == 0 {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+// BoxStarts returns the row and column of the top left cell of box boxId, boxes are numbered row by row
+func BoxStarts(boxId int) (int, int) {
+    boxRowStart := boxId / 3 * 3
+    boxColumnStart := boxId % 3 * 3
+    return boxRowStart, boxColumnStart
+}
+
+func getBoxIdFromCell(row int, col int) int {
+    return (row/3)*3 + (col / 3)
+}
+
+func getBoxStartsFromCell(row int, col int) (int, int) {
+    boxId := getBoxIdFromCell(row, col)
+    return BoxStarts(boxId)
+}
+
+// CellCandidates returns the candidates of a cell in ascending order
+func CellCandidates(game *Sudoku, row int, col int) []uint8 {
+    candidates := make([]uint8, 0)
+    for i := 1; i < 10; i++ {
+        if game.Candidates[row][col][i-1] {
+            candidates = append(candidates, uint8(i))
+        }
+    }
+    return candidates
+}
+
+func getMostConstrainedCell(game *Sudoku) (int, int) {
+    minCandidates := 10
+    var row, col, candidateCount int
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if game.Board[i][j] == 0 {
+                candidateCount = game.CandidatesCount[i][j]
+                if candidateCount < minCandidates {
+                    minCandidates = candidateCount
+                    row = i
+                    col = j
+                }
+            }
+        }
+    }
+    return row, col
+}
+
+func getRandomEmptyCell(board Board, rng *rand.Rand) (int, int) {
+    for {
+        row := rng.IntN(9)
+        col := rng.IntN(9)
+        if board[row][col] == 0 {
+            return row, col
+        }
+    }
+}
+
+// CellsSeeEachOther returns whether two cells share a row, column or box
+func CellsSeeEachOther(row1 int, col1 int, row2 int, col2 int) bool {
+    return (row1 == row2 || col1 == col2 || (row1/3 == row2/3 && col1/3 == col2/3))
+}
+
+// IsNumberComplete returns whether number is placed correctly in all nine of its cells
+func IsNumberComplete(game Sudoku, number uint8) bool {
+    if number == 0 {
+        return false
+    }
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if game.Solution[i][j] == number && game.Board[i][j] != number {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+func selectRandomCandidate(candidates []uint8, rng *rand.Rand) (uint8, error) {
+    if len(candidates) == 0 {
+        return 0, fmt.Errorf("No candidates available")
+    }
+    return candidates[rng.IntN(len(candidates))], nil
+}
+
+// ComputeCandidates sets the candidates of every empty cell to the digits it does not see
+func ComputeCandidates(game *Sudoku) {
+    game.Candidates = [9][9][9]bool{}
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            game.CandidatesCount[i][j] = 9
+            for k := 0; k < 9; k++ {
+                game.Candidates[i][j][k] = true
+            }
+        }
+    }
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if game.Board[i][j] != 0 {
+                updateCandidates(i, j, game.Board[i][j], game)
+            }
+        }
+    }
+}
+
+// ToggleCandidate adds or removes a candidate from 1 to 9 of a cell
+func ToggleCandidate(row int, col int, candidate int, game *Sudoku) {
+    game.Candidates[row][col][candidate-1] = !game.Candidates[row][col][candidate-1]
+}
+
+func updateCandidates(changedRow int, changedColumn int, insertedValue uint8, game *Sudoku) {
+    for i := 0; i < 9; i++ {
+        if game.Candidates[changedRow][i][insertedValue-1] {
+            game.Candidates[changedRow][i][insertedValue-1] = false
+            game.CandidatesCount[changedRow][i]--
+        }
+        if game.Candidates[i][changedColumn][insertedValue-1] {
+            game.Candidates[i][changedColumn][insertedValue-1] = false
+            game.CandidatesCount[i][changedColumn]--
+        }
+    }
+    boxRowStart, boxColumnStart := getBoxStartsFromCell(changedRow, changedColumn)
+    for i := boxRowStart; i < boxRowStart+3; i++ {
+        for j := boxColumnStart; j < boxColumnStart+3; j++ {
+            if game.Candidates[i][j][insertedValue-1] {
+                game.Candidates[i][j][insertedValue-1] = false
+                game.CandidatesCount[i][j]--
+            }
+        }
+    }
+}
+
+// WipeCandidates removes all candidates
+func WipeCandidates(game *Sudoku) {
+    game.Candidates = [9][9][9]bool{}
+}
@@ -0,0 +1,271 @@
+package sudoku
+
+import (
+    "context"
+    "errors"
+    "testing"
+)
+
+const (
+    testPuzzle   = "050008000002050006308072094980040010500006000103020800209030058000100602070260409"
+    testSolution = "654398721792451386318672594986543217527816943143927865269734158435189672871265439"
+)
+
+func mustParseBoard(t *testing.T, line string) Board {
+    t.Helper()
+    board, err := ParseBoard(line)
+    if err != nil {
+        t.Fatalf("could not parse %s: %v", line, err)
+    }
+    return board
+}
+
+func TestNewAndSolve(t *testing.T) {
+    tests := []struct {
+        name     string
+        puzzle   string
+        solution string
+        err      error
+    }{
+        {"unique solution", testPuzzle, testSolution, nil},
+        {"duplicate digits in a row", "550008000002050006308072094980040010500006000103020800209030058000100602070260409", "", ErrDuplicateDigits},
+        {"duplicate digits in a box", "050008000502000006308072094980040010500006000103020800209030058000100602070260409", "", ErrDuplicateDigits},
+        // r1c9 can only be a 9, which is already in its column
+        {"no solution", "123456780000000009000000000000000000000000000000000000000000000000000000000000000", "", ErrNoSolution},
+        {"multiple solutions", "000000000000000000000000000000000000000000000000000000000000000000000000000000000", "", ErrMultipleSolutions},
+        {"solved board", testSolution, testSolution, nil},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            board := mustParseBoard(t, test.puzzle)
+            game, err := New(board)
+            if !errors.Is(err, test.err) {
+                t.Fatalf("New returned %v, want %v", err, test.err)
+            }
+            solution, solveErr := Solve(board)
+            if !errors.Is(solveErr, test.err) {
+                t.Errorf("Solve returned %v, want %v", solveErr, test.err)
+            }
+            if err != nil {
+                var puzzleError *PuzzleError
+                if !errors.As(err, &puzzleError) {
+                    t.Errorf("%v is not a PuzzleError", err)
+                }
+                return
+            }
+            if game.Board != board {
+                t.Errorf("board is %s, want %s", game.Board, board)
+            }
+            if game.Solution.String() != test.solution || solution.String() != test.solution {
+                t.Errorf("solutions are %s and %s, want %s", game.Solution, solution, test.solution)
+            }
+        })
+    }
+}
+
+func TestGenerateStopsWithContext(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    // the maximum difficulty takes long enough that the cancellation is noticed first
+    _, err := Generate(ctx, GeneratorOptions{Difficulty: MaxDifficulty - 1, RequiredStrategy: "Skyscraper"}, 1, 2)
+    if !errors.Is(err, context.Canceled) {
+        t.Errorf("Generate returned %v, want %v", err, context.Canceled)
+    }
+}
+
+func TestGenerateRejectsInvalidArguments(t *testing.T) {
+    tests := []struct {
+        name       string
+        options    GeneratorOptions
+        numWorkers int
+    }{
+        {"invalid options", GeneratorOptions{Difficulty: MaxDifficulty + 1}, 1},
+        {"no workers", GeneratorOptions{Difficulty: 1}, 0},
+        {"negative workers", GeneratorOptions{Difficulty: 1}, -2},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            _, err := Generate(context.Background(), test.options, 1, test.numWorkers)
+            var optionsError *OptionsError
+            if !errors.As(err, &optionsError) {
+                t.Errorf("Generate returned %v, want an OptionsError", err)
+            }
+        })
+    }
+}
+
+func TestGenerate(t *testing.T) {
+    tests := []struct {
+        name    string
+        options GeneratorOptions
+    }{
+        {"fill", GeneratorOptions{Difficulty: 1}},
+        {"dig", GeneratorOptions{Method: DigMethod, Difficulty: 1, Symmetric: true}},
+        {"minimal", GeneratorOptions{Difficulty: 1, Minimal: true}},
+        {"clue counts", GeneratorOptions{Difficulty: 1, MinClues: 30, MaxClues: 40}},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            game, err := Generate(context.Background(), test.options, 1, 1)
+            if err != nil {
+                t.Fatalf("could not generate a sudoku: %v", err)
+            }
+            if solution, err := Solve(game.Board); err != nil || solution != game.Solution {
+                t.Errorf("solution of %s is %s, %v, want %s", game.Board, solution, err, game.Solution)
+            }
+            if path, _ := SolvePath(&game); PathDifficulty(path) != test.options.Difficulty {
+                t.Errorf("difficulty of %s is %d, want %d", game.Board, PathDifficulty(path), test.options.Difficulty)
+            }
+            numClues := CountClues(game.Board)
+            if numClues < test.options.MinClues || (test.options.MaxClues > 0 && numClues > test.options.MaxClues) {
+                t.Errorf("%s has %d clues, want between %d and %d", game.Board, numClues, test.options.MinClues, test.options.MaxClues)
+            }
+            if test.options.Minimal && !IsMinimal(game) {
+                t.Errorf("%s is not minimal", game.Board)
+            }
+            if test.options.Symmetric {
+                for i := 0; i < 9; i++ {
+                    for j := 0; j < 9; j++ {
+                        if (game.Board[i][j] == 0) != (game.Board[8-i][8-j] == 0) {
+                            t.Fatalf("clues of %s are not symmetric", game.Board)
+                        }
+                    }
+                }
+            }
+
+            again, err := Generate(context.Background(), test.options, 1, 1)
+            if err != nil || again.Board != game.Board {
+                t.Errorf("generating with the same seed gave %s, %v, want %s", again.Board, err, game.Board)
+            }
+        })
+    }
+}
+
+// getTestMask returns a mask of the first numClues cells, row by row
+func getTestMask(numClues int) *[9][9]bool {
+    var mask [9][9]bool
+    for n := 0; n < numClues; n++ {
+        mask[n/9][n%9] = true
+    }
+    return &mask
+}
+
+func TestValidate(t *testing.T) {
+    tests := []struct {
+        name    string
+        options GeneratorOptions
+        valid   bool
+    }{
+        {"default", GeneratorOptions{}, true},
+        {"fill", GeneratorOptions{Method: FillMethod, Difficulty: 3}, true},
+        {"symmetric dig", GeneratorOptions{Method: DigMethod, Symmetric: true}, true},
+        {"minimal with most min clues", GeneratorOptions{Minimal: true, MinClues: MaxMinimalClueCount}, true},
+        {"most min clues", GeneratorOptions{MinClues: 80}, true},
+        {"fewest max clues", GeneratorOptions{MaxClues: MinClueCount}, true},
+        {"hardest strategy", GeneratorOptions{Difficulty: 4, RequiredStrategy: "X-Wing", RequireHardest: true}, true},
+        {"harder than the strategy", GeneratorOptions{Difficulty: 5, RequiredStrategy: "Naked Pair"}, true},
+        {"mask with fewest clues", GeneratorOptions{Mask: getTestMask(MinClueCount)}, true},
+        {"mask with most clues", GeneratorOptions{Mask: getTestMask(80), Difficulty: 1}, true},
+        {"unknown method", GeneratorOptions{Method: "carve"}, false},
+        {"negative difficulty", GeneratorOptions{Difficulty: -1}, false},
+        {"difficulty too large", GeneratorOptions{Difficulty: MaxDifficulty + 1}, false},
+        {"symmetric fill", GeneratorOptions{Method: FillMethod, Symmetric: true}, false},
+        {"unknown strategy", GeneratorOptions{RequiredStrategy: "Unknown"}, false},
+        {"easier than the strategy", GeneratorOptions{Difficulty: 2, RequiredStrategy: "X-Wing"}, false},
+        {"harder than the hardest strategy", GeneratorOptions{Difficulty: 5, RequiredStrategy: "X-Wing", RequireHardest: true}, false},
+        {"hardest without strategy", GeneratorOptions{RequireHardest: true}, false},
+        {"negative min clues", GeneratorOptions{MinClues: -1}, false},
+        {"negative max clues", GeneratorOptions{MaxClues: -1}, false},
+        {"too few max clues", GeneratorOptions{MaxClues: MinClueCount - 1}, false},
+        {"min clues above max clues", GeneratorOptions{MinClues: 30, MaxClues: 25}, false},
+        {"too many min clues", GeneratorOptions{MinClues: 81}, false},
+        {"minimal with too many min clues", GeneratorOptions{Minimal: true, MinClues: MaxMinimalClueCount + 1}, false},
+        {"mask with too few clues", GeneratorOptions{Mask: getTestMask(MinClueCount - 1)}, false},
+        {"mask with too many clues", GeneratorOptions{Mask: getTestMask(81)}, false},
+        {"mask with dig", GeneratorOptions{Mask: getTestMask(30), Method: DigMethod}, false},
+        {"minimal mask", GeneratorOptions{Mask: getTestMask(30), Minimal: true}, false},
+        {"mask with min clues", GeneratorOptions{Mask: getTestMask(30), MinClues: 20}, false},
+        {"mask with max clues", GeneratorOptions{Mask: getTestMask(30), MaxClues: 40}, false},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            err := test.options.Validate()
+            if test.valid {
+                if err != nil {
+                    t.Errorf("Validate returned %v, want nil", err)
+                }
+                return
+            }
+            var optionsError *OptionsError
+            if !errors.As(err, &optionsError) {
+                t.Errorf("Validate returned %v, want an OptionsError", err)
+            }
+        })
+    }
+}
+
+func TestGenerateWithMask(t *testing.T) {
+    var mask [9][9]bool
+    for _, cell := range [][2]int{
+        {0, 0}, {0, 4}, {0, 8}, {1, 1}, {1, 3}, {1, 5}, {1, 7}, {2, 2}, {2, 6}, {3, 1}, {3, 4}, {3, 7},
+        {4, 0}, {4, 2}, {4, 6}, {4, 8}, {5, 1}, {5, 4}, {5, 7}, {6, 2}, {6, 6}, {7, 1}, {7, 3}, {7, 5},
+        {7, 7}, {8, 0}, {8, 4}, {8, 8},
+    } {
+        mask[cell[0]][cell[1]] = true
+    }
+    game, err := Generate(context.Background(), GeneratorOptions{Mask: &mask}, 1, 1)
+    if err != nil {
+        t.Fatalf("could not generate a sudoku for the mask: %v", err)
+    }
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if (game.Board[i][j] != 0) != mask[i][j] {
+                t.Fatalf("clues of %s do not match the mask", game.Board)
+            }
+        }
+    }
+    if solution, err := Solve(game.Board); err != nil || solution != game.Solution {
+        t.Errorf("solution of %s is %s, %v, want %s", game.Board, solution, err, game.Solution)
+    }
+}
+
+// setMaskLimits lowers the limits of maskSudoku until the test ends, so that it gives up quickly
+func setMaskLimits(t *testing.T, nodes int, searches int, attempts int) {
+    savedNodes, savedSearches, savedAttempts := maxMaskSearchNodes, maxMaskSearches, maxMaskAttempts
+    maxMaskSearchNodes, maxMaskSearches, maxMaskAttempts = nodes, searches, attempts
+    t.Cleanup(func() {
+        maxMaskSearchNodes, maxMaskSearches, maxMaskAttempts = savedNodes, savedSearches, savedAttempts
+    })
+}
+
+func TestGenerateWithMaskErrors(t *testing.T) {
+    var checkerboard [9][9]bool
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            checkerboard[i][j] = (i+j)%2 == 0
+        }
+    }
+    tests := []struct {
+        name    string
+        options GeneratorOptions
+        // limits of maskSudoku
+        nodes    int
+        searches int
+        attempts int
+        err      error
+    }{
+        // two full rows leave many ways to fill the other rows
+        {"not unique", GeneratorOptions{Mask: getTestMask(18)}, 1000, 3, 5, ErrMaskNotUnique},
+        // 41 clues leave nothing for the hard strategies to do
+        {"too easy", GeneratorOptions{Mask: &checkerboard, Difficulty: 4}, maxMaskSearchNodes, maxMaskSearches, 5, ErrMaskDifficulty},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            setMaskLimits(t, test.nodes, test.searches, test.attempts)
+            _, err := Generate(context.Background(), test.options, 1, 1)
+            if !errors.Is(err, test.err) {
+                t.Errorf("Generate returned %v, want %v", err, test.err)
+            }
+        })
+    }
+}
@@ -1,4 +1,4 @@
-package main
+package sudoku
 
 import (
     "fmt"
@@ -7,6 +7,7 @@ import (
     "slices"
 )
 
+// Context is the kind of unit a solution step is found in
 type Context int
 
 const (
@@ -30,14 +31,15 @@ func (context Context) String() string {
     return "Unknown"
 }
 
-func getCell(context Context, contextIdx int, cellIdx int) (int, int) {
+// ContextCell returns the row and column of the cellIdx-th cell of the contextIdx-th unit of context
+func ContextCell(context Context, contextIdx int, cellIdx int) (int, int) {
     switch context {
     case Row:
         return contextIdx, cellIdx
     case Column:
         return cellIdx, contextIdx
     case Box:
-        boxRowStart, boxColStart := getBoxStartsFromBoxId(contextIdx)
+        boxRowStart, boxColStart := BoxStarts(contextIdx)
         return boxRowStart + cellIdx/3, boxColStart + cellIdx%3
     case Cell:
         return contextIdx / 9, contextIdx % 9
@@ -50,15 +52,15 @@ func getContextCandidates(game *Sudoku, context Context, contextIdx int) map[int
     candidates := make(map[int][]uint8)
     switch context {
     case Cell:
-        row, col = getCell(context, contextIdx, 0)
-        if game.board[row][col] == 0 {
-            candidates[0] = getCandidates(game, row, col)
+        row, col = ContextCell(context, contextIdx, 0)
+        if game.Board[row][col] == 0 {
+            candidates[0] = CellCandidates(game, row, col)
         }
     default:
         for cellIdx := range 9 {
-            row, col = getCell(context, contextIdx, cellIdx)
-            if game.board[row][col] == 0 {
-                candidates[cellIdx] = getCandidates(game, row, col)
+            row, col = ContextCell(context, contextIdx, cellIdx)
+            if game.Board[row][col] == 0 {
+                candidates[cellIdx] = CellCandidates(game, row, col)
             }
         }
     }
@@ -81,11 +83,11 @@ func getContextIdx(context Context, row int, col int) int {
 func inSameBox(indices []int, context Context, contextIdx int) bool {
     var row, col, idx int
     var boxRowStart, boxColStart, boxRowEnd, boxColEnd int
-    row, col = getCell(context, contextIdx, indices[0])
+    row, col = ContextCell(context, contextIdx, indices[0])
     boxRowStart, boxColStart = getBoxStartsFromCell(row, col)
     boxRowEnd, boxColEnd = boxRowStart+2, boxColStart+2
     for _, idx = range indices[1:] {
-        row, col = getCell(context, contextIdx, idx)
+        row, col = ContextCell(context, contextIdx, idx)
         if row < boxRowStart || row > boxRowEnd ||
             col < boxColStart || col > boxColEnd {
             return false
@@ -96,18 +98,18 @@ func inSameBox(indices []int, context Context, contextIdx int) bool {
 
 func inSameContext(targetContext Context, indices []int, context Context, contextIdx int) bool {
     var idx, otherRow, otherCol int
-    row, col := getCell(context, contextIdx, indices[0])
+    row, col := ContextCell(context, contextIdx, indices[0])
     switch targetContext {
     case Row:
         for _, idx = range indices[1:] {
-            otherRow, _ = getCell(context, contextIdx, idx)
+            otherRow, _ = ContextCell(context, contextIdx, idx)
             if otherRow != row {
                 return false
             }
         }
     case Column:
         for _, idx = range indices[1:] {
-            _, otherCol = getCell(context, contextIdx, idx)
+            _, otherCol = ContextCell(context, contextIdx, idx)
             if otherCol != col {
                 return false
             }
@@ -115,7 +117,7 @@ func inSameContext(targetContext Context, indices []int, context Context, contex
     case Box:
         boxId := getBoxIdFromCell(row, col)
         for _, idx = range indices[1:] {
-            otherRow, otherCol = getCell(context, contextIdx, idx)
+            otherRow, otherCol = ContextCell(context, contextIdx, idx)
             if getBoxIdFromCell(otherRow, otherCol) != boxId {
                 return false
             }
@@ -133,6 +135,7 @@ func resolveRowCol(context1 Context, contextIdx1 int, context2 Context, contextI
     panic("Invalid contexts")
 }
 
+// Effect is what a solution step does to its target cells
 type Effect int
 
 const (
@@ -140,37 +143,66 @@ const (
     RemoveCandidate
 )
 
+// SolutionStep is a deduction of a strategy, it places TargetValues in or removes them as candidates
+// from TargetCells, which are found in the units SourceIndices of SourceContext
 type SolutionStep struct {
-    strategy      string
-    description   string
-    sourceContext Context
-    sourceIndices []int
-    targetCells   [][]int
-    targetValues  []uint8
-    effectType    Effect
-}
-
+    // name and difficulty of the strategy, Hints sets them from the strategy that found the step
+    Strategy      string
+    Difficulty    int
+    Description   string
+    SourceContext Context
+    SourceIndices []int
+    TargetCells   [][]int
+    TargetValues  []uint8
+    EffectType    Effect
+}
+
+// Apply places the digits or removes the candidates of step
 func (step SolutionStep) Apply(game *Sudoku) {
-    switch step.effectType {
+    switch step.EffectType {
     case PlaceNumber:
-        for i, cell := range step.targetCells {
-            game.board[cell[0]][cell[1]] = step.targetValues[i]
-            updateCandidates(cell[0], cell[1], step.targetValues[i], game)
+        for i, cell := range step.TargetCells {
+            game.Board[cell[0]][cell[1]] = step.TargetValues[i]
+            updateCandidates(cell[0], cell[1], step.TargetValues[i], game)
         }
     case RemoveCandidate:
-        for i, cell := range step.targetCells {
-            game.candidates[cell[0]][cell[1]][step.targetValues[i]-1] = false
-            game.candidatesCount[cell[0]][cell[1]]--
+        for i, cell := range step.TargetCells {
+            if game.Candidates[cell[0]][cell[1]][step.TargetValues[i]-1] {
+                game.Candidates[cell[0]][cell[1]][step.TargetValues[i]-1] = false
+                game.CandidatesCount[cell[0]][cell[1]]--
+            }
+        }
+    }
+}
+
+// makesProgress returns whether step is well-formed and applying it changes game, i.e. it places
+// a digit in an empty cell or removes a candidate that is still there
+func (step SolutionStep) makesProgress(game *Sudoku) bool {
+    if len(step.TargetCells) != len(step.TargetValues) {
+        return false
+    }
+    progress := false
+    for i, cell := range step.TargetCells {
+        value := step.TargetValues[i]
+        if len(cell) != 2 || cell[0] < 0 || cell[0] > 8 || cell[1] < 0 || cell[1] > 8 || value < 1 || value > 9 {
+            return false
+        }
+        switch step.EffectType {
+        case PlaceNumber:
+            progress = progress || game.Board[cell[0]][cell[1]] == 0
+        case RemoveCandidate:
+            progress = progress || game.Candidates[cell[0]][cell[1]][value-1]
         }
     }
+    return progress
 }
 
 func isDuplicateEffect(steps []SolutionStep, row int, col int, value uint8) bool {
     for _, step := range steps {
-        for i, targetCell := range step.targetCells {
+        for i, targetCell := range step.TargetCells {
             if targetCell[0] == row &&
                 targetCell[1] == col &&
-                step.targetValues[i] == value {
+                step.TargetValues[i] == value {
                 return true
             }
         }
@@ -208,8 +240,8 @@ func getContextPossibilitiesByCandidate(game *Sudoku, context Context, contextId
     possibilities := make(map[uint8][]int)
     switch context {
     case Cell:
-        row, col = getCell(context, contextIdx, 0)
-        candidates = getCandidates(game, row, col)
+        row, col = ContextCell(context, contextIdx, 0)
+        candidates = CellCandidates(game, row, col)
         for _, candidate := range candidates {
             possibilities[candidate] = []int{0}
         }
@@ -229,16 +261,16 @@ func getCandidatePossibilitiesInContext(game *Sudoku, context Context, contextId
     var possibilities []int
     switch context {
     case Cell:
-        row, col = getCell(context, contextIdx, 0)
-        if game.board[row][col] == 0 && game.candidates[row][col][candidate-1] {
+        row, col = ContextCell(context, contextIdx, 0)
+        if game.Board[row][col] == 0 && game.Candidates[row][col][candidate-1] {
             possibilities = []int{0}
         } else {
             possibilities = []int{}
         }
     default:
         for cellIdx := range 9 {
-            row, col = getCell(context, contextIdx, cellIdx)
-            if game.board[row][col] == 0 && game.candidates[row][col][candidate-1] {
+            row, col = ContextCell(context, contextIdx, cellIdx)
+            if game.Board[row][col] == 0 && game.Candidates[row][col][candidate-1] {
                 possibilities = append(possibilities, cellIdx)
             }
         }
@@ -297,25 +329,23 @@ func findSets[keyType constraints.Integer, valueType constraints.Integer](candid
     return setIndices
 }
 
-type SolveStrategy func(*Sudoku) []SolutionStep
-
 func nakedSingle(game *Sudoku) []SolutionStep {
     var steps []SolutionStep
     var candidates []uint8
     var description string
     for i := 0; i < 9; i++ {
         for j := 0; j < 9; j++ {
-            if game.board[i][j] == 0 && game.candidatesCount[i][j] == 1 {
-                candidates = getCandidates(game, i, j)
+            if game.Board[i][j] == 0 && game.CandidatesCount[i][j] == 1 {
+                candidates = CellCandidates(game, i, j)
                 description = fmt.Sprintf("r%dc%d can only be %d", i+1, j+1, candidates[0])
                 steps = append(steps, SolutionStep{
-                    strategy:      "Naked Single",
-                    description:   description,
-                    sourceContext: Cell,
-                    sourceIndices: []int{9*i + j},
-                    targetCells:   [][]int{{i, j}},
-                    targetValues:  []uint8{candidates[0]},
-                    effectType:    PlaceNumber,
+                    Strategy:      "Naked Single",
+                    Description:   description,
+                    SourceContext: Cell,
+                    SourceIndices: []int{9*i + j},
+                    TargetCells:   [][]int{{i, j}},
+                    TargetValues:  []uint8{candidates[0]},
+                    EffectType:    PlaceNumber,
                 })
             }
         }
@@ -335,22 +365,22 @@ func hiddenSingle(game *Sudoku) []SolutionStep {
             for candidateIdx := range 9 {
                 count = 0
                 for cell_idx := 0; cell_idx < 9; cell_idx++ {
-                    row, col = getCell(context, contextIdx, cell_idx)
-                    if game.board[row][col] == 0 && game.candidates[row][col][candidateIdx] {
+                    row, col = ContextCell(context, contextIdx, cell_idx)
+                    if game.Board[row][col] == 0 && game.Candidates[row][col][candidateIdx] {
                         count++
                         lastIdx = cell_idx
                     }
                 }
                 if count == 1 {
                     contextStr := context.String()
-                    row, col = getCell(context, contextIdx, lastIdx)
+                    row, col = ContextCell(context, contextIdx, lastIdx)
                     number := uint8(candidateIdx + 1)
 
                     for _, step := range steps {
                         // avoid steps with duplicate effects
-                        if step.targetCells[0][0] == row &&
-                            step.targetCells[0][1] == col &&
-                            step.targetValues[0] == number {
+                        if step.TargetCells[0][0] == row &&
+                            step.TargetCells[0][1] == col &&
+                            step.TargetValues[0] == number {
                             continue candidateLoop
                         }
                     }
@@ -361,13 +391,13 @@ func hiddenSingle(game *Sudoku) []SolutionStep {
                         contextStr,
                         contextIdx+1)
                     steps = append(steps, SolutionStep{
-                        strategy:      "Hidden Single",
-                        description:   description,
-                        sourceContext: context,
-                        sourceIndices: []int{contextIdx},
-                        targetCells:   [][]int{{row, col}},
-                        targetValues:  []uint8{number},
-                        effectType:    PlaceNumber,
+                        Strategy:      "Hidden Single",
+                        Description:   description,
+                        SourceContext: context,
+                        SourceIndices: []int{contextIdx},
+                        TargetCells:   [][]int{{row, col}},
+                        TargetValues:  []uint8{number},
+                        EffectType:    PlaceNumber,
                     })
                 }
             }
@@ -396,7 +426,7 @@ func nakedSet(game *Sudoku, setSize int, strategyName string) []SolutionStep {
                     if slices.Contains(set, otherIdx) {
                         continue
                     }
-                    row, col = getCell(context, contextIdx, otherIdx)
+                    row, col = ContextCell(context, contextIdx, otherIdx)
                     for _, candidate := range otherCandidates {
                         if slices.Contains(setCandidates, candidate) {
                             if isDuplicateEffect(steps, row, col, candidate) {
@@ -417,17 +447,17 @@ func nakedSet(game *Sudoku, setSize int, strategyName string) []SolutionStep {
                 }
                 description += "have to go in"
                 for _, cellIdx := range set {
-                    row, col = getCell(context, contextIdx, cellIdx)
+                    row, col = ContextCell(context, contextIdx, cellIdx)
                     description += fmt.Sprintf(" r%dc%d", row+1, col+1)
                 }
                 steps = append(steps, SolutionStep{
-                    strategy:      strategyName,
-                    description:   description,
-                    sourceContext: context,
-                    sourceIndices: []int{contextIdx},
-                    targetCells:   targetCells,
-                    targetValues:  targetValues,
-                    effectType:    RemoveCandidate,
+                    Strategy:      strategyName,
+                    Description:   description,
+                    SourceContext: context,
+                    SourceIndices: []int{contextIdx},
+                    TargetCells:   targetCells,
+                    TargetValues:  targetValues,
+                    EffectType:    RemoveCandidate,
                 })
             }
         }
@@ -467,8 +497,8 @@ func hiddenSet(game *Sudoku, setSize int, strategyName string) []SolutionStep {
                 targetCells = [][]int{}
                 targetValues = []uint8{}
                 for _, cellIdx = range setIndices {
-                    row, col = getCell(context, contextIdx, cellIdx)
-                    candidates = getCandidates(game, row, col)
+                    row, col = ContextCell(context, contextIdx, cellIdx)
+                    candidates = CellCandidates(game, row, col)
                     for _, candidate = range candidates {
                         if slices.Contains(setCandidates, candidate) {
                             continue
@@ -490,17 +520,17 @@ func hiddenSet(game *Sudoku, setSize int, strategyName string) []SolutionStep {
                 }
                 description += "can only go in"
                 for _, cellIdx := range setIndices {
-                    row, col = getCell(context, contextIdx, cellIdx)
+                    row, col = ContextCell(context, contextIdx, cellIdx)
                     description += fmt.Sprintf(" r%dc%d", row+1, col+1)
                 }
                 steps = append(steps, SolutionStep{
-                    strategy:      strategyName,
-                    description:   description,
-                    sourceContext: context,
-                    sourceIndices: []int{contextIdx},
-                    targetCells:   targetCells,
-                    targetValues:  targetValues,
-                    effectType:    RemoveCandidate,
+                    Strategy:      strategyName,
+                    Description:   description,
+                    SourceContext: context,
+                    SourceIndices: []int{contextIdx},
+                    TargetCells:   targetCells,
+                    TargetValues:  targetValues,
+                    EffectType:    RemoveCandidate,
                 })
             }
         }
@@ -540,18 +570,18 @@ func pointingGroup(game *Sudoku) []SolutionStep {
                 if !inSameContext(targetContext, possibilities[candidate], Box, boxId) {
                     continue
                 }
-                sourceRow, sourceCol = getCell(Box, boxId, possibilities[candidate][0])
+                sourceRow, sourceCol = ContextCell(Box, boxId, possibilities[candidate][0])
                 contextIdx = getContextIdx(targetContext, sourceRow, sourceCol)
                 targetCells = [][]int{}
                 targetValues = []uint8{}
                 for idx := range 9 {
-                    row, col = getCell(targetContext, contextIdx, idx)
+                    row, col = ContextCell(targetContext, contextIdx, idx)
                     targetBoxId = getBoxIdFromCell(row, col)
                     if targetBoxId == boxId {
                         continue
-                    } else if game.board[row][col] != 0 {
+                    } else if game.Board[row][col] != 0 {
                         continue
-                    } else if !game.candidates[row][col][candidate-1] {
+                    } else if !game.Candidates[row][col][candidate-1] {
                         continue
                     } else if isDuplicateEffect(steps, row, col, candidate) {
                         continue
@@ -568,13 +598,13 @@ func pointingGroup(game *Sudoku) []SolutionStep {
                     candidate,
                     boxId+1)
                 steps = append(steps, SolutionStep{
-                    strategy:      "Pointing Group",
-                    description:   description,
-                    sourceContext: Box,
-                    sourceIndices: []int{boxId},
-                    targetCells:   targetCells,
-                    targetValues:  targetValues,
-                    effectType:    RemoveCandidate,
+                    Strategy:      "Pointing Group",
+                    Description:   description,
+                    SourceContext: Box,
+                    SourceIndices: []int{boxId},
+                    TargetCells:   targetCells,
+                    TargetValues:  targetValues,
+                    EffectType:    RemoveCandidate,
                 })
             }
         }
@@ -601,9 +631,9 @@ func boxReduction(game *Sudoku) []SolutionStep {
                 } else if !inSameBox(possibilities[candidate], context, contextIdx) {
                     continue
                 }
-                row, col = getCell(context, contextIdx, possibilities[candidate][0])
+                row, col = ContextCell(context, contextIdx, possibilities[candidate][0])
                 boxId = getBoxIdFromCell(row, col)
-                boxRowStart, boxColStart = getBoxStartsFromBoxId(boxId)
+                boxRowStart, boxColStart = BoxStarts(boxId)
                 boxRowEnd, boxColEnd = boxRowStart+2, boxColStart+2
                 targetCells = [][]int{}
                 targetValues = []uint8{}
@@ -614,9 +644,9 @@ func boxReduction(game *Sudoku) []SolutionStep {
                     for col = boxColStart; col <= boxColEnd; col++ {
                         if context == Column && col == contextIdx {
                             continue
-                        } else if game.board[row][col] != 0 {
+                        } else if game.Board[row][col] != 0 {
                             continue
-                        } else if !game.candidates[row][col][candidate-1] {
+                        } else if !game.Candidates[row][col][candidate-1] {
                             continue
                         } else if isDuplicateEffect(steps, row, col, candidate) {
                             continue
@@ -634,13 +664,13 @@ func boxReduction(game *Sudoku) []SolutionStep {
                     context.String(),
                     contextIdx+1)
                 steps = append(steps, SolutionStep{
-                    strategy:      "Box Reduction",
-                    description:   description,
-                    sourceContext: context,
-                    sourceIndices: []int{contextIdx},
-                    targetCells:   targetCells,
-                    targetValues:  targetValues,
-                    effectType:    RemoveCandidate,
+                    Strategy:      "Box Reduction",
+                    Description:   description,
+                    SourceContext: context,
+                    SourceIndices: []int{contextIdx},
+                    TargetCells:   targetCells,
+                    TargetValues:  targetValues,
+                    EffectType:    RemoveCandidate,
                 })
             }
         }
@@ -673,9 +703,9 @@ func basicFish(game *Sudoku, fishSize int, strategyName string) []SolutionStep {
                         row, col = resolveRowCol(context, cellIdx, otherContext, otherContextIdx)
                         if slices.Contains(contextIndices, cellIdx) {
                             continue
-                        } else if game.board[row][col] != 0 {
+                        } else if game.Board[row][col] != 0 {
                             continue
-                        } else if !game.candidates[row][col][candidate-1] {
+                        } else if !game.Candidates[row][col][candidate-1] {
                             continue
                         } else if isDuplicateEffect(steps, row, col, candidate) {
                             continue
@@ -696,13 +726,13 @@ func basicFish(game *Sudoku, fishSize int, strategyName string) []SolutionStep {
                     description += fmt.Sprintf(" %d", contextIdx+1)
                 }
                 steps = append(steps, SolutionStep{
-                    strategy:      strategyName,
-                    description:   description,
-                    sourceContext: context,
-                    sourceIndices: contextIndices,
-                    targetCells:   targetCells,
-                    targetValues:  targetValues,
-                    effectType:    RemoveCandidate,
+                    Strategy:      strategyName,
+                    Description:   description,
+                    SourceContext: context,
+                    SourceIndices: contextIndices,
+                    TargetCells:   targetCells,
+                    TargetValues:  targetValues,
+                    EffectType:    RemoveCandidate,
                 })
             }
         }
@@ -773,13 +803,13 @@ func skyscraper(game *Sudoku) []SolutionStep {
                 }
                 for row = range 9 {
                     for col = range 9 {
-                        if game.board[row][col] != 0 {
+                        if game.Board[row][col] != 0 {
                             continue
-                        } else if !game.candidates[row][col][candidate-1] {
+                        } else if !game.Candidates[row][col][candidate-1] {
                             continue
-                        } else if !cellsSeeEachOther(row, col, sourceCells[0][0], sourceCells[0][1]) {
+                        } else if !CellsSeeEachOther(row, col, sourceCells[0][0], sourceCells[0][1]) {
                             continue
-                        } else if !cellsSeeEachOther(row, col, sourceCells[1][0], sourceCells[1][1]) {
+                        } else if !CellsSeeEachOther(row, col, sourceCells[1][0], sourceCells[1][1]) {
                             continue
                         } else if row == sourceCells[0][0] && col == sourceCells[0][1] {
                             continue
@@ -802,13 +832,13 @@ func skyscraper(game *Sudoku) []SolutionStep {
                                           sourceCells[1][1]+1,
                                           candidate)
                 steps = append(steps, SolutionStep{
-                    strategy:      "Skyscraper",
-                    description:   description,
-                    sourceContext: otherContext,
-                    sourceIndices: otherContextIndices,
-                    targetCells:   targetCells,
-                    targetValues:  targetValues,
-                    effectType:    RemoveCandidate,
+                    Strategy:      "Skyscraper",
+                    Description:   description,
+                    SourceContext: otherContext,
+                    SourceIndices: otherContextIndices,
+                    TargetCells:   targetCells,
+                    TargetValues:  targetValues,
+                    EffectType:    RemoveCandidate,
                 })
             }
         }
@@ -816,74 +846,109 @@ func skyscraper(game *Sudoku) []SolutionStep {
     return steps
 }
 
-var solveStrategies = []SolveStrategy{
-    nakedSingle,
-    hiddenSingle,
-    nakedPair,
-    nakedTriple,
-    nakedQuad,
-    pointingGroup,
-    boxReduction,
-    hiddenPair,
-    hiddenTriple,
-    hiddenQuad,
-    xWing,
-    swordfish,
-    jellyfish,
-    skyscraper,
-    // yWing,
-}
-
-var strategyDifficulty = map[string]int{
-    "Naked Single":   1,
-    "Hidden Single":  1,
-    "Naked Pair":     2,
-    "Naked Triple":   2,
-    "Naked Quad":     2,
-    "Pointing Group": 2,
-    "Box Reduction":  2,
-    "Hidden Pair":    3,
-    "Hidden Triple":  3,
-    "Hidden Quad":    3,
-    "X-Wing":         4,
-    "Swordfish":      4,
-    "Jellyfish":      4,
-    "Skyscraper":     4,
-    "Y-Wing":         4,
-}
-
-var maxDifficulty = 5
-
-var validDifficulties = []int{0, 1, 2, 3, 4, maxDifficulty} // 0 for random difficulty
-
-func rateDifficulty(game *Sudoku) int {
+// MaxDifficulty is the difficulty of sudokus the strategies cannot solve
+const MaxDifficulty = 5
+
+// ValidDifficulties are the difficulties GeneratorOptions accept
+var ValidDifficulties = []int{0, 1, 2, 3, 4, MaxDifficulty} // 0 for random difficulty
+
+// Rate returns the difficulty of the hardest strategy needed to solve game, or MaxDifficulty
+// if the strategies cannot solve it
+func Rate(game *Sudoku) int {
+    path, solved := SolvePath(game)
+    if !solved {
+        return MaxDifficulty
+    }
+    return PathDifficulty(path)
+}
+
+// SolvePath repeatedly applies the easiest strategy that finds steps until the game is solved
+// and returns all applied steps, solved is false if none of the strategies made any progress
+func SolvePath(game *Sudoku) (path []SolutionStep, solved bool) {
+    solved = WalkSolvePath(game, func(step SolutionStep, _ *Sudoku) {
+        path = append(path, step)
+    })
+    return path, solved
+}
+
+// WalkSolvePath runs the strategy loop of SolvePath on a copy of game and calls visit with
+// every step right after it is applied to the copy
+func WalkSolvePath(game *Sudoku, visit func(step SolutionStep, game *Sudoku)) bool {
     gameCopy := *game
-    var steps []SolutionStep
-    var difficulty int
-    for !isSolved(gameCopy.board) {
-        for _, strategy := range solveStrategies {
-            steps = strategy(&gameCopy)
-            if len(steps) > 0 {
-                break
-            }
-        }
+    for !IsSolved(gameCopy.Board) {
+        steps := Hints(&gameCopy)
         if len(steps) == 0 {
-            return maxDifficulty
+            return false
         }
         for _, step := range steps {
             step.Apply(&gameCopy)
-            difficulty = max(difficulty, strategyDifficulty[step.strategy])
+            visit(step, &gameCopy)
+        }
+    }
+    return true
+}
+
+// Hints returns the steps found by the easiest strategy that finds any that make progress, with the
+// name and difficulty of the strategy, steps that would not change game are dropped, so that
+// solving cannot get stuck on a strategy that keeps finding them
+func Hints(game *Sudoku) []SolutionStep {
+    for _, strategy := range registry.Load().strategies {
+        var steps []SolutionStep
+        for _, step := range strategy.Find(game) {
+            if step.makesProgress(game) {
+                step.Strategy = strategy.Name()
+                step.Difficulty = strategy.Difficulty()
+                steps = append(steps, step)
+            }
+        }
+        if len(steps) > 0 {
+            return steps
         }
     }
+    return nil
+}
+
+// PathDifficulty returns the difficulty of the hardest strategy used by path
+func PathDifficulty(path []SolutionStep) int {
+    var difficulty int
+    for _, step := range path {
+        difficulty = max(difficulty, step.Difficulty)
+    }
     return difficulty
 }
 
-func solvableUsingStrategies(game *Sudoku, strategies []SolveStrategy) bool {
+// NumericRating gives a finer rating than the difficulty, it is the sum of the difficulties of all
+// steps of the solve path of game, if the strategies got stuck, each unsolved cell adds the maximum difficulty
+func NumericRating(game *Sudoku, path []SolutionStep, solved bool) int {
+    rating := 0
+    placedNumbers := 0
+    for _, step := range path {
+        rating += step.Difficulty
+        if step.EffectType == PlaceNumber {
+            placedNumbers += len(step.TargetCells)
+        }
+    }
+    if !solved {
+        rating += MaxDifficulty * (81 - CountClues(game.Board) - placedNumbers)
+    }
+    return rating
+}
+
+func pathUsesStrategy(path []SolutionStep, strategyName string) bool {
+    for _, step := range path {
+        if step.Strategy == strategyName {
+            return true
+        }
+    }
+    return false
+}
+
+func solvableUsingStrategies(game *Sudoku, strategies []Strategy) bool {
     gameCopy := *game
     var steps []SolutionStep
-    for !isSolved(gameCopy.board) {
+    for !IsSolved(gameCopy.Board) {
         for _, strategy := range strategies {
-            steps = strategy(&gameCopy)
+            steps = strategy.Find(&gameCopy)
             if len(steps) > 0 {
                 break
             }
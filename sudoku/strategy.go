@@ -0,0 +1,136 @@
+package sudoku
+
+import (
+    "fmt"
+    "sync"
+    "sync/atomic"
+)
+
+// Strategy is a solving technique, Find returns all steps it allows in the current state of a sudoku,
+// Hints sets the Strategy and Difficulty of each step to its Name and Difficulty
+type Strategy interface {
+    Name() string
+    // Difficulty is between 1 and MaxDifficulty-1, strategies are tried from easiest to hardest
+    Difficulty() int
+    Find(game *Sudoku) []SolutionStep
+}
+
+// funcStrategy is a Strategy given by its name, difficulty and find function
+type funcStrategy struct {
+    name       string
+    difficulty int
+    find       func(game *Sudoku) []SolutionStep
+}
+
+func (strategy funcStrategy) Name() string {
+    return strategy.name
+}
+
+func (strategy funcStrategy) Difficulty() int {
+    return strategy.difficulty
+}
+
+func (strategy funcStrategy) Find(game *Sudoku) []SolutionStep {
+    return strategy.find(game)
+}
+
+// NewStrategy returns a Strategy that finds its steps with find
+func NewStrategy(name string, difficulty int, find func(game *Sudoku) []SolutionStep) Strategy {
+    return funcStrategy{name: name, difficulty: difficulty, find: find}
+}
+
+// the strategies of the solver, in the order it tries them
+var builtinStrategies = []Strategy{
+    NewStrategy("Naked Single", 1, nakedSingle),
+    NewStrategy("Hidden Single", 1, hiddenSingle),
+    NewStrategy("Naked Pair", 2, nakedPair),
+    NewStrategy("Naked Triple", 2, nakedTriple),
+    NewStrategy("Naked Quad", 2, nakedQuad),
+    NewStrategy("Pointing Group", 2, pointingGroup),
+    NewStrategy("Box Reduction", 2, boxReduction),
+    NewStrategy("Hidden Pair", 3, hiddenPair),
+    NewStrategy("Hidden Triple", 3, hiddenTriple),
+    NewStrategy("Hidden Quad", 3, hiddenQuad),
+    NewStrategy("X-Wing", 4, xWing),
+    NewStrategy("Swordfish", 4, swordfish),
+    NewStrategy("Jellyfish", 4, jellyfish),
+    NewStrategy("Skyscraper", 4, skyscraper),
+    // NewStrategy("Y-Wing", 4, yWing),
+}
+
+// strategyRegistry holds the registered strategies sorted by difficulty, registries are never modified
+// once they are stored, so solving only needs an atomic load while generator workers run in parallel
+type strategyRegistry struct {
+    strategies []Strategy
+    byName     map[string]Strategy
+}
+
+var (
+    registry      atomic.Pointer[strategyRegistry]
+    registryMutex sync.Mutex
+)
+
+func init() {
+    registry.Store(&strategyRegistry{byName: map[string]Strategy{}})
+    for _, strategy := range builtinStrategies {
+        if err := Register(strategy); err != nil {
+            panic(err)
+        }
+    }
+}
+
+// Register adds strategy to the strategies used for solving, rating, hints and generation, it is tried
+// after all registered strategies of the same or a lower difficulty and before the harder ones
+func Register(strategy Strategy) error {
+    registryMutex.Lock()
+    defer registryMutex.Unlock()
+    current := registry.Load()
+    name, difficulty := strategy.Name(), strategy.Difficulty()
+    if name == "" {
+        return fmt.Errorf("strategy needs a name")
+    } else if _, ok := current.byName[name]; ok {
+        return fmt.Errorf("strategy %q is already registered", name)
+    } else if difficulty < 1 || difficulty >= MaxDifficulty {
+        return fmt.Errorf("difficulty of strategy %q must be between 1 and %d", name, MaxDifficulty-1)
+    }
+
+    next := &strategyRegistry{
+        strategies: make([]Strategy, 0, len(current.strategies)+1),
+        byName:     make(map[string]Strategy, len(current.byName)+1),
+    }
+    inserted := false
+    for _, registered := range current.strategies {
+        if !inserted && registered.Difficulty() > difficulty {
+            next.strategies = append(next.strategies, strategy)
+            inserted = true
+        }
+        next.strategies = append(next.strategies, registered)
+    }
+    if !inserted {
+        next.strategies = append(next.strategies, strategy)
+    }
+    for _, registered := range next.strategies {
+        next.byName[registered.Name()] = registered
+    }
+    registry.Store(next)
+    return nil
+}
+
+// Strategies returns the registered strategies in the order the solver tries them
+func Strategies() []Strategy {
+    return append([]Strategy(nil), registry.Load().strategies...)
+}
+
+// LookupStrategy returns the registered strategy called name
+func LookupStrategy(name string) (Strategy, bool) {
+    strategy, ok := registry.Load().byName[name]
+    return strategy, ok
+}
+
+// StrategyDifficulty returns the difficulty of the registered strategy called name, 0 if there is none
+func StrategyDifficulty(name string) int {
+    if strategy, ok := LookupStrategy(name); ok {
+        return strategy.Difficulty()
+    }
+    return 0
+}
@@ -0,0 +1,163 @@
+package sudoku
+
+import (
+    "slices"
+    "testing"
+)
+
+// restoreRegistry undoes the registrations of a test when it ends
+func restoreRegistry(t *testing.T) {
+    saved := registry.Load()
+    t.Cleanup(func() {
+        registry.Store(saved)
+    })
+}
+
+func getNames(strategies []Strategy) []string {
+    var names []string
+    for _, strategy := range strategies {
+        names = append(names, strategy.Name())
+    }
+    return names
+}
+
+func findNothing(game *Sudoku) []SolutionStep {
+    return nil
+}
+
+func TestRegisterOrdersByDifficulty(t *testing.T) {
+    restoreRegistry(t)
+    builtinNames := getNames(builtinStrategies)
+    if names := getNames(Strategies()); !slices.Equal(names, builtinNames) {
+        t.Fatalf("strategies are %q, want the builtin strategies %q", names, builtinNames)
+    }
+
+    tests := []struct {
+        name       string
+        difficulty int
+        // name of the strategy that has to come right before the registered one
+        after string
+    }{
+        {"Easy Test", 1, "Hidden Single"},
+        {"Medium Test", 3, "Hidden Quad"},
+        {"Hard Test", 4, "Skyscraper"},
+        {"Second Easy Test", 1, "Easy Test"},
+    }
+    for _, test := range tests {
+        if err := Register(NewStrategy(test.name, test.difficulty, findNothing)); err != nil {
+            t.Fatalf("could not register %s: %v", test.name, err)
+        }
+        names := getNames(Strategies())
+        idx := slices.Index(names, test.name)
+        if idx < 1 || names[idx-1] != test.after {
+            t.Errorf("%s is registered in %q, want it right after %s", test.name, names, test.after)
+        }
+    }
+
+    strategies := Strategies()
+    for i := 1; i < len(strategies); i++ {
+        if strategies[i-1].Difficulty() > strategies[i].Difficulty() {
+            t.Errorf("%s comes after the harder %s", strategies[i].Name(), strategies[i-1].Name())
+        }
+    }
+}
+
+func TestRegisterRejectsInvalidStrategies(t *testing.T) {
+    restoreRegistry(t)
+    tests := []struct {
+        name     string
+        strategy Strategy
+    }{
+        {"empty name", NewStrategy("", 2, findNothing)},
+        {"duplicate builtin name", NewStrategy("X-Wing", 4, findNothing)},
+        {"duplicate name with another difficulty", NewStrategy("Naked Single", 3, findNothing)},
+        {"difficulty 0", NewStrategy("Too Easy", 0, findNothing)},
+        {"maximum difficulty", NewStrategy("Too Hard", MaxDifficulty, findNothing)},
+    }
+    numStrategies := len(Strategies())
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if err := Register(test.strategy); err == nil {
+                t.Errorf("registering %q with difficulty %d succeeded, want an error", test.strategy.Name(), test.strategy.Difficulty())
+            }
+            if len(Strategies()) != numStrategies {
+                t.Errorf("a rejected strategy changed the number of strategies")
+            }
+        })
+    }
+}
+
+func TestLookupStrategy(t *testing.T) {
+    restoreRegistry(t)
+    if err := Register(NewStrategy("Custom", 3, findNothing)); err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        name       string
+        found      bool
+        difficulty int
+    }{
+        {"Naked Single", true, 1},
+        {"X-Wing", true, 4},
+        {"Custom", true, 3},
+        {"x-wing", false, 0},
+        {"X-Wing ", false, 0},
+        {"", false, 0},
+    }
+    for _, test := range tests {
+        strategy, ok := LookupStrategy(test.name)
+        if ok != test.found {
+            t.Errorf("found %q is %v, want %v", test.name, ok, test.found)
+        } else if ok && (strategy.Name() != test.name || strategy.Difficulty() != test.difficulty) {
+            t.Errorf("%q is %q with difficulty %d, want difficulty %d", test.name, strategy.Name(), strategy.Difficulty(), test.difficulty)
+        }
+        if difficulty := StrategyDifficulty(test.name); difficulty != test.difficulty {
+            t.Errorf("difficulty of %q is %d, want %d", test.name, difficulty, test.difficulty)
+        }
+    }
+}
+
+func TestHintsDropsStepsWithoutProgress(t *testing.T) {
+    restoreRegistry(t)
+    registry.Store(&strategyRegistry{byName: map[string]Strategy{}})
+    game, err := New(mustParseBoard(t, testPuzzle))
+    if err != nil {
+        t.Fatal(err)
+    }
+    // r1c1 is empty and r1c2 holds a 5, so 5 is not a candidate of r1c1
+    game.Candidates[0][0] = [9]bool{true, true}
+    game.CandidatesCount[0][0] = 2
+    withoutProgress := []SolutionStep{
+        {Description: "digit in a filled cell", TargetCells: [][]int{{0, 1}}, TargetValues: []uint8{5}, EffectType: PlaceNumber},
+        {Description: "removed candidate", TargetCells: [][]int{{0, 0}}, TargetValues: []uint8{5}, EffectType: RemoveCandidate},
+        {Description: "more values than cells", TargetCells: [][]int{{0, 0}}, TargetValues: []uint8{1, 2}, EffectType: RemoveCandidate},
+        {Description: "cell outside the board", TargetCells: [][]int{{9, 0}}, TargetValues: []uint8{1}, EffectType: RemoveCandidate},
+        {Description: "value outside 1 to 9", TargetCells: [][]int{{0, 0}}, TargetValues: []uint8{0}, EffectType: RemoveCandidate},
+        {Description: "no targets", EffectType: RemoveCandidate},
+    }
+    progress := SolutionStep{Description: "present candidate", TargetCells: [][]int{{0, 0}}, TargetValues: []uint8{2}, EffectType: RemoveCandidate}
+    strategies := []Strategy{
+        NewStrategy("Stuck", 1, func(game *Sudoku) []SolutionStep {
+            return withoutProgress
+        }),
+        NewStrategy("Progress", 2, func(game *Sudoku) []SolutionStep {
+            return append(slices.Clone(withoutProgress), progress)
+        }),
+    }
+    for _, strategy := range strategies {
+        if err := Register(strategy); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    steps := Hints(&game)
+    if len(steps) != 1 {
+        t.Fatalf("got %d hints, want only the step of Progress that makes progress: %+v", len(steps), steps)
+    }
+    if steps[0].Description != progress.Description {
+        t.Errorf("hint is %q, want %q", steps[0].Description, progress.Description)
+    }
+    if steps[0].Strategy != "Progress" || steps[0].Difficulty != 2 {
+        t.Errorf("hint is from %q with difficulty %d, want Progress with difficulty 2", steps[0].Strategy, steps[0].Difficulty)
+    }
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "math/rand/v2"
+    "slices"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// a transformation maps a board to an equivalent one, random parameters like the digit
+// permutation are fixed on creation so that puzzle and solution are transformed alike
+type boardTransformation func(board [9][9]uint8) [9][9]uint8
+
+var transformationNames = []string{
+    "relabel",
+    "rotate",
+    "mirror",
+    "flip",
+    "transpose",
+    "bands",
+    "stacks",
+    "rows",
+    "columns",
+}
+
+// "random" applies the geometric transformations each with a chance of one half and all other
+// transformations, which are random themselves, resulting in a random equivalent board
+const randomTransformation = "random"
+
+var geometricTransformations = []string{"rotate", "mirror", "flip", "transpose"}
+
+func identityOrder() [9]int {
+    return [9]int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+}
+
+// getRandomLineOrder returns a random order of rows (or columns) that keeps them in their bands,
+// permuting the bands if shuffleBands is set and the lines within each band if shuffleLines is set
+func getRandomLineOrder(shuffleBands bool, shuffleLines bool, rng *rand.Rand) [9]int {
+    var order [9]int
+    bandOrder := permutations3[0]
+    if shuffleBands {
+        bandOrder = permutations3[rng.IntN(6)]
+    }
+    for band := 0; band < 3; band++ {
+        lineOrder := permutations3[0]
+        if shuffleLines {
+            lineOrder = permutations3[rng.IntN(6)]
+        }
+        for line := 0; line < 3; line++ {
+            order[3*band+line] = 3*bandOrder[band] + lineOrder[line]
+        }
+    }
+    return order
+}
+
+func permuteLines(board [9][9]uint8, rowOrder [9]int, colOrder [9]int) [9][9]uint8 {
+    var permuted [9][9]uint8
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            permuted[i][j] = board[rowOrder[i]][colOrder[j]]
+        }
+    }
+    return permuted
+}
+
+func newTransformation(name string, rng *rand.Rand) (boardTransformation, error) {
+    reversed := [9]int{8, 7, 6, 5, 4, 3, 2, 1, 0}
+    switch name {
+    case "relabel":
+        var labels [10]uint8
+        for i, digit := range rng.Perm(9) {
+            labels[i+1] = uint8(digit + 1)
+        }
+        return func(board [9][9]uint8) [9][9]uint8 {
+            for i := 0; i < 9; i++ {
+                for j := 0; j < 9; j++ {
+                    board[i][j] = labels[board[i][j]]
+                }
+            }
+            return board
+        }, nil
+    case "rotate":
+        // rotate clockwise by 90 degrees
+        return func(board [9][9]uint8) [9][9]uint8 {
+            return permuteLines(transposeBoard(board), identityOrder(), reversed)
+        }, nil
+    case "mirror":
+        return func(board [9][9]uint8) [9][9]uint8 {
+            return permuteLines(board, identityOrder(), reversed)
+        }, nil
+    case "flip":
+        return func(board [9][9]uint8) [9][9]uint8 {
+            return permuteLines(board, reversed, identityOrder())
+        }, nil
+    case "transpose":
+        return transposeBoard, nil
+    case "bands", "rows":
+        rowOrder := getRandomLineOrder(name == "bands", name == "rows", rng)
+        return func(board [9][9]uint8) [9][9]uint8 {
+            return permuteLines(board, rowOrder, identityOrder())
+        }, nil
+    case "stacks", "columns":
+        colOrder := getRandomLineOrder(name == "stacks", name == "columns", rng)
+        return func(board [9][9]uint8) [9][9]uint8 {
+            return permuteLines(board, identityOrder(), colOrder)
+        }, nil
+    }
+    return nil, newUsageError("unknown transformation %q, must be %q or one of %q",
+        name, randomTransformation, transformationNames)
+}
+
+// parseTransformations creates the transformations of a comma separated list of names,
+// random parameters are drawn from rng each time this is called
+func parseTransformations(list string, rng *rand.Rand) ([]boardTransformation, error) {
+    var transformations []boardTransformation
+    for _, name := range strings.Split(list, ",") {
+        name = strings.TrimSpace(name)
+        names := []string{name}
+        if name == randomTransformation {
+            names = nil
+            for _, name := range transformationNames {
+                if !slices.Contains(geometricTransformations, name) || rng.IntN(2) == 0 {
+                    names = append(names, name)
+                }
+            }
+        }
+        for _, name := range names {
+            transformation, err := newTransformation(name, rng)
+            if err != nil {
+                return nil, err
+            }
+            transformations = append(transformations, transformation)
+        }
+    }
+    return transformations, nil
+}
+
+// transformSudoku applies the transformations to board and solution, the candidates are recomputed
+func transformSudoku(game sudoku.Sudoku, transformations []boardTransformation) sudoku.Sudoku {
+    transformed := sudoku.Empty()
+    transformed.Board = game.Board
+    transformed.Solution = game.Solution
+    for _, transformation := range transformations {
+        transformed.Board = transformation(transformed.Board)
+        transformed.Solution = transformation(transformed.Solution)
+    }
+    sudoku.ComputeCandidates(&transformed)
+    return transformed
+}
+
+// runTransform reads puzzles from input, one 81 character line each, and prints numVariants
+// transformed variants of each puzzle as lines containing the puzzle and its solution
+func runTransform(input io.Reader, list string, numVariants int, seed int) error {
+    if seed == -1 {
+        seed = rand.Int()
+    }
+    rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+    // check the list before reading any input
+    if _, err := parseTransformations(list, rng); err != nil {
+        return err
+    }
+    scanner := bufio.NewScanner(input)
+    lineNumber := 0
+    for scanner.Scan() {
+        lineNumber++
+        fields := strings.Fields(scanner.Text())
+        if len(fields) == 0 {
+            continue
+        }
+        board, err := sudoku.ParseBoard(fields[0])
+        if err != nil {
+            return fmt.Errorf("line %d: %w", lineNumber, err)
+        }
+        game, err := sudoku.New(board)
+        if err != nil {
+            return fmt.Errorf("line %d: %w", lineNumber, err)
+        }
+        for i := 0; i < numVariants; i++ {
+            transformations, _ := parseTransformations(list, rng)
+            variant := transformSudoku(game, transformations)
+            fmt.Printf("%s %s\n", variant.Board.String(), variant.Solution.String())
+        }
+    }
+    return scanner.Err()
+}
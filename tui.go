@@ -1,28 +1,35 @@
 package main
 
 import (
+    "context"
     "fmt"
-    "os"
 
     "github.com/charmbracelet/bubbles/help"
     "github.com/charmbracelet/bubbles/key"
     tea "github.com/charmbracelet/bubbletea"
     "github.com/charmbracelet/lipgloss"
     "github.com/charmbracelet/lipgloss/table"
+
+    "github.com/kleinjohann/sugoku/sudoku"
 )
 
 type model struct {
-    game       Sudoku
-    tipsGame   Sudoku
+    game       sudoku.Sudoku
+    tipsGame   sudoku.Sudoku
     editable   [9][9]bool
-    difficulty int
+    options    sudoku.GeneratorOptions
     cursor     [2]int
     keys       keyMap
     help       help.Model
-    strategies []SolutionStep
+    strategies []sudoku.SolutionStep
     tips       string
+    // shown until the next key press
+    message    string
     width      int
     cores      int
+    pool       *puzzlePool
+    // loaded puzzles to play before generating new ones
+    collection []sudoku.Sudoku
 }
 
 type keyMap struct {
@@ -39,6 +46,8 @@ type keyMap struct {
     Delete            key.Binding
     ComputeCandidates key.Binding
     WipeCandidates    key.Binding
+    ExportPencilMarks key.Binding
+    ShareCode         key.Binding
     ToggleTips        key.Binding
     ApplyTips         key.Binding
     NewGame           key.Binding
@@ -98,6 +107,14 @@ var keys = keyMap{
         key.WithKeys("C"),
         key.WithHelp("C", "wipe all pencil marks"),
     ),
+    ExportPencilMarks: key.NewBinding(
+        key.WithKeys("E"),
+        key.WithHelp("E", "export pencil mark grid"),
+    ),
+    ShareCode: key.NewBinding(
+        key.WithKeys("s"),
+        key.WithHelp("s", "show share code"),
+    ),
     ToggleTips: key.NewBinding(
         key.WithKeys("t"),
         key.WithHelp("t", "toggle tips"),
@@ -125,11 +142,35 @@ func (k keyMap) FullHelp() [][]key.Binding {
         {k.Up, k.Down, k.Left, k.Right,
             k.Up3, k.Down3, k.Left3, k.Right3,
             k.Number, k.Candidate, k.Delete,
-            k.ComputeCandidates, k.WipeCandidates,
+            k.ComputeCandidates, k.WipeCandidates, k.ExportPencilMarks, k.ShareCode,
             k.ToggleTips, k.NewGame, k.Quit},
     }
 }
 
+// getConfigurableBindings maps the names of the bindings that can be changed in the keys section
+// of the config to them, digits and pencil marks are fixed since their keys determine the number
+func (k *keyMap) getConfigurableBindings() map[string]*key.Binding {
+    return map[string]*key.Binding{
+        "up":                  &k.Up,
+        "down":                &k.Down,
+        "left":                &k.Left,
+        "right":               &k.Right,
+        "up3":                 &k.Up3,
+        "down3":               &k.Down3,
+        "left3":               &k.Left3,
+        "right3":              &k.Right3,
+        "delete":              &k.Delete,
+        "compute-candidates":  &k.ComputeCandidates,
+        "wipe-candidates":     &k.WipeCandidates,
+        "export-pencil-marks": &k.ExportPencilMarks,
+        "share-code":          &k.ShareCode,
+        "toggle-tips":         &k.ToggleTips,
+        "apply-tips":          &k.ApplyTips,
+        "new-game":            &k.NewGame,
+        "quit":                &k.Quit,
+    }
+}
+
 var cursorBackground = lipgloss.Color("3")
 var visibleFromCursorBackground = lipgloss.Color("18")
 var cursorNumberBackground = lipgloss.Color("6")
@@ -140,32 +181,103 @@ var completedNumberForeground = lipgloss.Color("2")
 var editableForeground = lipgloss.Color("4")
 var uneditableForeground = lipgloss.Color("15")
 
-func initialModel(difficulty int, seed int, cores int) model {
-    game := generateSudokuParallel(difficulty, seed, cores)
+// getThemeColors maps the names of the colors in the theme section of the config to them
+func getThemeColors() map[string]*lipgloss.Color {
+    return map[string]*lipgloss.Color{
+        "cursor-background":              &cursorBackground,
+        "visible-from-cursor-background": &visibleFromCursorBackground,
+        "cursor-number-background":       &cursorNumberBackground,
+        "cursor-number-foreground":       &cursorNumberForeground,
+        "cursor-candidates-foreground":   &cursorCandidatesForeground,
+        "wrong-number-foreground":        &wrongNumberForeground,
+        "completed-number-foreground":    &completedNumberForeground,
+        "editable-foreground":            &editableForeground,
+        "uneditable-foreground":          &uneditableForeground,
+    }
+}
+
+// newGame takes a puzzle from the pool if possible and refills the pool in the background,
+// with a fixed seed the puzzle is always generated to stay reproducible
+func newGame(options sudoku.GeneratorOptions, seed int, cores int, pool *puzzlePool) (sudoku.Sudoku, error) {
+    if pool == nil || seed != -1 {
+        return sudoku.Generate(context.Background(), options, seed, cores)
+    }
+    game, ok := pool.take(options)
+    if !ok {
+        var err error
+        if game, err = sudoku.Generate(context.Background(), options, seed, cores); err != nil {
+            return game, err
+        }
+    }
+    go pool.refill(options)
+    return game, nil
+}
+
+func initialModel(options sudoku.GeneratorOptions, seed int, cores int, pool *puzzlePool) (model, error) {
+    game, err := newGame(options, seed, cores, pool)
+    if err != nil {
+        return model{}, err
+    }
+    return newModel(game, options, cores, pool), nil
+}
+
+func newModel(game sudoku.Sudoku, options sudoku.GeneratorOptions, cores int, pool *puzzlePool) model {
     editable := [9][9]bool{}
     for i := 0; i < 9; i++ {
         for j := 0; j < 9; j++ {
-            if game.board[i][j] == 0 {
+            if game.Board[i][j] == 0 {
                 editable[i][j] = true
             }
         }
     }
     tipsGame := game
-    game.candidates = [9][9][9]bool{}
+    game.Candidates = [9][9][9]bool{}
     m := model{
         game:     game,
         tipsGame: tipsGame,
         editable: editable,
-        difficulty: difficulty,
+        options:  options,
         cursor:   [2]int{4, 4},
         keys:     keys,
         help:     help.New(),
         cores:    cores,
+        pool:     pool,
     }
     m.help.ShowAll = true
     return m
 }
 
+// withPencilMarks makes the candidates of game, e.g. from an imported mid-solve state,
+// the user's pencil marks and the basis for tips
+func (m model) withPencilMarks(game sudoku.Sudoku) model {
+    m.game.Candidates = game.Candidates
+    countCandidates(&m.game)
+    m.tipsGame.Candidates = game.Candidates
+    countCandidates(&m.tipsGame)
+    return m
+}
+
+// withState continues the game with the entered digits and pencil marks of state,
+// e.g. from a share code, where the model was created from the givens only
+func (m model) withState(state sudoku.Sudoku) model {
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            number := state.Board[i][j]
+            if !m.editable[i][j] || number == 0 {
+                m.game.Candidates[i][j] = state.Candidates[i][j]
+                continue
+            }
+            m.game.Board[i][j] = number
+            if m.game.Solution[i][j] == number {
+                m.tipsGame.Board[i][j] = number
+            }
+        }
+    }
+    countCandidates(&m.game)
+    sudoku.ComputeCandidates(&m.tipsGame)
+    return m
+}
+
 func (m model) Init() tea.Cmd {
     return nil
 }
@@ -177,6 +289,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
         m.width = msg.Width
 
     case tea.KeyMsg:
+        m.message = ""
 
         switch {
 
@@ -185,7 +298,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
             return m, tea.Quit
 
         case key.Matches(msg, keys.NewGame):
-            return initialModel(m.difficulty, -1, m.cores), nil
+            if len(m.collection) > 0 {
+                next := newModel(m.collection[0], m.options, m.cores, m.pool)
+                next.collection = m.collection[1:]
+                return next, nil
+            }
+            next, err := initialModel(m.options, -1, m.cores, m.pool)
+            if err != nil {
+                m.message = fmt.Sprintf("Could not generate a new game: %v", err)
+                return m, nil
+            }
+            return next, nil
 
         case key.Matches(msg, keys.Up):
             m.cursor[0] = (m.cursor[0] - 1 + 9) % 9
@@ -214,33 +337,50 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
         case key.Matches(msg, keys.Number):
             if m.editable[m.cursor[0]][m.cursor[1]] {
                 number := uint8(msg.String()[0] - '0')
-                m.game.board[m.cursor[0]][m.cursor[1]] = number
-                if m.game.solution[m.cursor[0]][m.cursor[1]] == number {
-                    m.tipsGame.board[m.cursor[0]][m.cursor[1]] = number
-                    computeCandidates(&m.tipsGame)
+                m.game.Board[m.cursor[0]][m.cursor[1]] = number
+                if m.game.Solution[m.cursor[0]][m.cursor[1]] == number {
+                    m.tipsGame.Board[m.cursor[0]][m.cursor[1]] = number
+                    sudoku.ComputeCandidates(&m.tipsGame)
                 }
             }
 
         case key.Matches(msg, keys.Candidate):
             number := getNumberFromShiftedDigit(msg.String())
             if m.editable[m.cursor[0]][m.cursor[1]] {
-                toggleCandidate(m.cursor[0], m.cursor[1], number, &m.game)
+                sudoku.ToggleCandidate(m.cursor[0], m.cursor[1], number, &m.game)
             }
 
         case key.Matches(msg, keys.Delete):
             if m.editable[m.cursor[0]][m.cursor[1]] {
-                if m.game.board[m.cursor[0]][m.cursor[1]] == 0 {
-                    m.game.candidates[m.cursor[0]][m.cursor[1]] = [9]bool{}
+                if m.game.Board[m.cursor[0]][m.cursor[1]] == 0 {
+                    m.game.Candidates[m.cursor[0]][m.cursor[1]] = [9]bool{}
                 } else {
-                    m.game.board[m.cursor[0]][m.cursor[1]] = 0
+                    m.game.Board[m.cursor[0]][m.cursor[1]] = 0
                 }
             }
 
         case key.Matches(msg, keys.ComputeCandidates):
-            m.game.candidates = m.tipsGame.candidates
+            m.game.Candidates = m.tipsGame.Candidates
 
         case key.Matches(msg, keys.WipeCandidates):
-            wipeCandidates(&m.game)
+            sudoku.WipeCandidates(&m.game)
+
+        case key.Matches(msg, keys.ExportPencilMarks):
+            path, err := exportPencilMarks(m.game)
+            if err != nil {
+                m.message = fmt.Sprintf("Could not export pencil marks: %v", err)
+            } else {
+                m.message = fmt.Sprintf("Exported pencil marks to %s", path)
+            }
+
+        case key.Matches(msg, keys.ShareCode):
+            var givens [9][9]bool
+            for i := 0; i < 9; i++ {
+                for j := 0; j < 9; j++ {
+                    givens[i][j] = !m.editable[i][j]
+                }
+            }
+            m.message = fmt.Sprintf("Share code: %s", encodeShareCode(m.game, givens))
 
         case key.Matches(msg, keys.ToggleTips):
             toggleTips(&m)
@@ -275,7 +415,7 @@ func (m model) View() string {
     tableWidth := lipgloss.Width(renderedTable)
     tableHeight := lipgloss.Height(renderedTable)
 
-    if isValidSolvedBoard(m.game.board) {
+    if sudoku.IsValidSolvedBoard(m.game.Board) {
         m.help.ShowAll = false
         m.tips = ""
         winMessage := lipgloss.NewStyle().Foreground(completedNumberForeground).Render("You won!")
@@ -290,6 +430,9 @@ func (m model) View() string {
     }
 
     helpView = lipgloss.JoinVertical(lipgloss.Left, helpView, "\n", m.tips)
+    if len(m.message) > 0 {
+        helpView = lipgloss.JoinVertical(lipgloss.Left, helpView, "\n", m.message)
+    }
 
     return lipgloss.JoinHorizontal(lipgloss.Top,
         renderedTable,
@@ -301,14 +444,14 @@ func (m model) View() string {
 func getCellStyle(m model, row int, col int) lipgloss.Style {
     var foreground lipgloss.Color
     var background lipgloss.Color
-    number := m.game.board[row][col]
+    number := m.game.Board[row][col]
     cursorRow := m.cursor[0]
     cursorCol := m.cursor[1]
-    cursorNumber := m.game.board[cursorRow][cursorCol]
-    if cellsSeeEachOther(row, col, cursorRow, cursorCol) {
+    cursorNumber := m.game.Board[cursorRow][cursorCol]
+    if sudoku.CellsSeeEachOther(row, col, cursorRow, cursorCol) {
         background = visibleFromCursorBackground
     }
-    if number == 0 && cursorNumber != 0 && m.game.candidates[row][col][cursorNumber-1] {
+    if number == 0 && cursorNumber != 0 && m.game.Candidates[row][col][cursorNumber-1] {
         background = cursorNumberBackground
         foreground = cursorCandidatesForeground
     }
@@ -326,24 +469,24 @@ func getCellStyle(m model, row int, col int) lipgloss.Style {
     if !m.editable[row][col] {
         foreground = uneditableForeground
     }
-    if numberIsComplete(m.game, number) {
+    if sudoku.IsNumberComplete(m.game, number) {
         foreground = completedNumberForeground
     }
-    if number != 0 && number != m.game.solution[row][col] {
+    if number != 0 && number != m.game.Solution[row][col] {
         foreground = wrongNumberForeground
     }
     return lipgloss.NewStyle().Foreground(foreground).Background(background)
 }
 
-func getCellString(game Sudoku, row int, col int, font asciiFont, height int, width int) string {
+func getCellString(game sudoku.Sudoku, row int, col int, font asciiFont, height int, width int) string {
     var digitString string
     var background string
-    digit := game.board[row][col]
+    digit := game.Board[row][col]
     if digit != 0 {
         digitString = font.numbers[int(digit)]
         background = font.background
     } else {
-        candidates := getCandidates(&game, row, col)
+        candidates := sudoku.CellCandidates(&game, row, col)
         digitString = getCandidatesString(candidates)
         background = " "
     }
@@ -390,7 +533,7 @@ func contains(s []uint8, e uint8) bool {
 }
 
 func getBoxString(boxId int, m model, font asciiFont, height int, width int) string {
-    boxRowStart, boxColStart := getBoxStartsFromBoxId(boxId)
+    boxRowStart, boxColStart := sudoku.BoxStarts(boxId)
     var boxString string
     var cellString string
     var cellStyle lipgloss.Style
@@ -442,21 +585,19 @@ func getNumberFromShiftedDigit(digit string) int {
 }
 
 func updateTipsString(m *model) {
-    if !isValidBoard(m.game.board) {
+    if !sudoku.IsValidBoard(m.game.Board) {
         m.tips = "You made a mistake!"
         return
     }
-    for strategy := range solveStrategies {
-        steps := solveStrategies[strategy](&m.tipsGame)
-        if len(steps) > 0 {
-            m.strategies = steps
-            m.tips = steps[0].strategy + ":\n"
-            for step := range steps {
-                m.tips += fmt.Sprintf("%s\n", steps[step].description)
-            }
-            m.tips += "\nPress 'T' to apply all tips"
-            return
+    steps := sudoku.Hints(&m.tipsGame)
+    if len(steps) > 0 {
+        m.strategies = steps
+        m.tips = steps[0].Strategy + ":\n"
+        for step := range steps {
+            m.tips += fmt.Sprintf("%s\n", steps[step].Description)
         }
+        m.tips += "\nPress 'T' to apply all tips"
+        return
     }
     m.tips = "No hints available"
 }
@@ -466,7 +607,7 @@ func applyTips(m *model) {
         step.Apply(&m.tipsGame)
         step.Apply(&m.game)
     }
-    m.strategies = []SolutionStep{}
+    m.strategies = []sudoku.SolutionStep{}
     updateTipsString(m)
 }
 
@@ -479,10 +620,24 @@ func toggleTips(m *model) {
     }
 }
 
-func runTui(difficulty int, seed int, cores int) {
-    p := tea.NewProgram(initialModel(difficulty, seed, cores))
-    if _, err := p.Run(); err != nil {
-        fmt.Printf("Error: %v", err)
-        os.Exit(1)
+// initialTuiModel starts with the given games one after another, afterwards
+// or if there are none, new games are generated according to options
+func initialTuiModel(games []sudoku.Sudoku, options sudoku.GeneratorOptions, seed int, cores int, poolSize int) (model, error) {
+    var pool *puzzlePool
+    if poolSize > 0 {
+        // without a cache directory, puzzles are simply generated on demand
+        pool, _ = newPuzzlePool(poolSize)
+    }
+    if len(games) == 0 {
+        return initialModel(options, seed, cores, pool)
     }
+    m := newModel(games[0], options, cores, pool)
+    m.collection = games[1:]
+    return m, nil
+}
+
+func runTui(m model) error {
+    p := tea.NewProgram(m)
+    _, err := p.Run()
+    return err
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/kleinjohann/sugoku/sudoku"
+)
+
+// boardConflict is a digit that appears more than once in a row, column or box
+type boardConflict struct {
+    unit  string
+    digit uint8
+    cells [][2]int
+}
+
+func formatCells(cells [][2]int) string {
+    names := make([]string, len(cells))
+    for i, cell := range cells {
+        names[i] = fmt.Sprintf("r%dc%d", cell[0]+1, cell[1]+1)
+    }
+    return strings.Join(names, ", ")
+}
+
+// findConflicts lists all duplicate digits of board by row, column and box
+func findConflicts(board [9][9]uint8) []boardConflict {
+    var conflicts []boardConflict
+    for _, unit := range []string{"Row", "Column", "Box"} {
+        for i := 0; i < 9; i++ {
+            var cellsByDigit [9][][2]int
+            for j := 0; j < 9; j++ {
+                row, col := i, j
+                if unit == "Column" {
+                    row, col = j, i
+                } else if unit == "Box" {
+                    boxRowStart, boxColumnStart := sudoku.BoxStarts(i)
+                    row, col = boxRowStart+j/3, boxColumnStart+j%3
+                }
+                if digit := board[row][col]; digit != 0 {
+                    cellsByDigit[digit-1] = append(cellsByDigit[digit-1], [2]int{row, col})
+                }
+            }
+            for k, cells := range cellsByDigit {
+                if len(cells) > 1 {
+                    conflicts = append(conflicts, boardConflict{
+                        unit:  fmt.Sprintf("%s %d", unit, i+1),
+                        digit: uint8(k + 1),
+                        cells: cells,
+                    })
+                }
+            }
+        }
+    }
+    return conflicts
+}
+
+// findDeadCells lists the empty cells of game whose candidates are all ruled out by the digits they see
+func findDeadCells(game *sudoku.Sudoku) [][2]int {
+    var cells [][2]int
+    for i := 0; i < 9; i++ {
+        for j := 0; j < 9; j++ {
+            if game.Board[i][j] == 0 && game.CandidatesCount[i][j] == 0 {
+                cells = append(cells, [2]int{i, j})
+            }
+        }
+    }
+    return cells
+}
+
+// runValidate prints a report of everything that keeps board from being a proper puzzle
+// with exactly one solution and returns whether it is one
+func runValidate(board [9][9]uint8) bool {
+    fmt.Printf("Puzzle: %s\n", sudoku.Board(board).String())
+    valid := true
+
+    conflicts := findConflicts(board)
+    if len(conflicts) > 0 {
+        valid = false
+        fmt.Println("Duplicates:")
+        for _, conflict := range conflicts {
+            fmt.Printf("  %s: %d in %s\n", conflict.unit, conflict.digit, formatCells(conflict.cells))
+        }
+    }
+
+    game := sudoku.Sudoku{Board: board}
+    sudoku.ComputeCandidates(&game)
+    if deadCells := findDeadCells(&game); len(deadCells) > 0 {
+        valid = false
+        fmt.Printf("Cells without candidates: %s\n", formatCells(deadCells))
+    }
+
+    if len(conflicts) > 0 {
+        fmt.Println("Solutions: none because of the duplicates")
+    } else {
+        solutions := sudoku.FindSolutions(game, 2)
+        switch len(solutions) {
+        case 0:
+            valid = false
+            fmt.Println("Solutions: none")
+        case 1:
+            fmt.Println("Solutions: 1")
+        default:
+            valid = false
+            fmt.Println("Solutions: at least 2, for example:")
+            printBoard(solutions[0])
+            printBoard(solutions[1])
+            var differences []string
+            for i := 0; i < 9; i++ {
+                for j := 0; j < 9; j++ {
+                    if solutions[0][i][j] != solutions[1][i][j] {
+                        differences = append(differences, fmt.Sprintf("r%dc%d (%d or %d)",
+                            i+1, j+1, solutions[0][i][j], solutions[1][i][j]))
+                    }
+                }
+            }
+            fmt.Printf("Differing cells: %s\n", strings.Join(differences, ", "))
+        }
+    }
+
+    if valid {
+        fmt.Println("Result: valid")
+    } else {
+        fmt.Println("Result: invalid")
+    }
+    return valid
+}